@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sdkopen/sdkopen-go/common/env"
+	"github.com/sdkopen/sdkopen-go/database"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	env.Load()
+
+	connector, err := database.NewDefaultConnector(database.WithAutoMigration(false))
+	if err != nil {
+		return err
+	}
+
+	db := connector.Connect()
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	return database.RunMigrationCommand(migrator, args, os.Stdout)
+}
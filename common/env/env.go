@@ -4,19 +4,21 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/sdkopen/sdkopen-go/logging"
 )
 
 const (
-	errorIntegerParse string = "could not parse %s, permited int value, got %v: %w"
-	errorBooleanParse string = "could not parse %s, permited 'true' or 'false', got %v: %w"
+	errorIntegerParse  string = "could not parse %s, permited int value, got %v: %w"
+	errorBooleanParse  string = "could not parse %s, permited 'true' or 'false', got %v: %w"
+	errorDurationParse string = "could not parse %s, permited duration value (e.g. 30s, 5m), got %v: %w"
 )
 
 var (
-	SQL_DB_DRIVER               = "postgres"
-	SQL_DB_PORT                 = 5432
+	SQL_DB_DRIVER               = defaultSqlDBDriver
+	SQL_DB_PORT                 = defaultSqlDBPort
 	SQL_DB_NAME                 = ""
 	SQL_DB_SSL_MODE             = ""
 	SQL_DB_URL                  = ""
@@ -24,12 +26,26 @@ var (
 	SQL_DB_PASSWORD             = ""
 	SQL_DB_EXEC_MIGRATION       = false
 	SQL_DB_MIGRATION_SOURCE_URL = ""
+	SQL_DB_MAX_OPEN_CONNS       = defaultSqlDBMaxOpenConns
+	SQL_DB_MAX_IDLE_CONNS       = defaultSqlDBMaxIdleConns
+	SQL_DB_CONN_MAX_LIFETIME    = defaultSqlDBConnMaxLifetime
+	SQL_DB_CONN_MAX_IDLE_TIME   = defaultSqlDBConnMaxIdleTime
+	SQL_DB_REPLICA_URLS         = ""
+	SQL_DB_REPLICA_HEALTH_CHECK = defaultSqlDBReplicaHealthCheck
+	SQL_DB_MIGRATION_LOCK_MODE  = defaultSqlDBMigrationLockMode
+	SQL_DB_MIGRATION_LOCK_WAIT  = defaultSqlDBMigrationLockWait
 	SERVER_PORT                 = 8080
 	RABBITMQ_URL                = ""
 	RABBITMQ_PORT               = 5672
 	RABBITMQ_USERNAME           = ""
 	RABBITMQ_PASSWORD           = ""
 	RABBITMQ_VHOST              = ""
+	RABBITMQ_PUBLISH_BUFFER     = 0
+
+	CONNECT_RETRY_MAX_ATTEMPTS     = 0
+	CONNECT_RETRY_INITIAL_INTERVAL = 500 * time.Millisecond
+	CONNECT_RETRY_MAX_INTERVAL     = 10 * time.Second
+	CONNECT_RETRY_MAX_WAIT         = time.Minute
 )
 
 func Load() {
@@ -47,6 +63,10 @@ func Load() {
 	SQL_DB_DRIVER = os.Getenv("SQL_DB_DRIVER")
 
 	SQL_DB_MIGRATION_SOURCE_URL = os.Getenv("SQL_DB_MIGRATION_SOURCE_URL")
+	SQL_DB_REPLICA_URLS = os.Getenv("SQL_DB_REPLICA_URLS")
+	if lockMode := os.Getenv("SQL_DB_MIGRATION_LOCK_MODE"); lockMode != "" {
+		SQL_DB_MIGRATION_LOCK_MODE = lockMode
+	}
 
 	RABBITMQ_URL = os.Getenv("RABBITMQ_URL")
 	RABBITMQ_USERNAME = os.Getenv("RABBITMQ_USERNAME")
@@ -67,10 +87,54 @@ func validateAndLoad() error {
 		return err
 	}
 
+	if err := convertToInt(&RABBITMQ_PUBLISH_BUFFER, "RABBITMQ_PUBLISH_BUFFER"); err != nil {
+		return err
+	}
+
 	if err := convertBoolEnv(&SQL_DB_EXEC_MIGRATION, "SQL_DB_EXEC_MIGRATION"); err != nil {
 		return err
 	}
 
+	if err := convertToInt(&SQL_DB_MAX_OPEN_CONNS, "SQL_DB_MAX_OPEN_CONNS"); err != nil {
+		return err
+	}
+
+	if err := convertToInt(&SQL_DB_MAX_IDLE_CONNS, "SQL_DB_MAX_IDLE_CONNS"); err != nil {
+		return err
+	}
+
+	if err := convertToDuration(&SQL_DB_CONN_MAX_LIFETIME, "SQL_DB_CONN_MAX_LIFETIME"); err != nil {
+		return err
+	}
+
+	if err := convertToDuration(&SQL_DB_CONN_MAX_IDLE_TIME, "SQL_DB_CONN_MAX_IDLE_TIME"); err != nil {
+		return err
+	}
+
+	if err := convertToDuration(&SQL_DB_REPLICA_HEALTH_CHECK, "SQL_DB_REPLICA_HEALTH_CHECK"); err != nil {
+		return err
+	}
+
+	if err := convertToDuration(&SQL_DB_MIGRATION_LOCK_WAIT, "SQL_DB_MIGRATION_LOCK_WAIT"); err != nil {
+		return err
+	}
+
+	if err := convertToInt(&CONNECT_RETRY_MAX_ATTEMPTS, "CONNECT_RETRY_MAX_ATTEMPTS"); err != nil {
+		return err
+	}
+
+	if err := convertToDuration(&CONNECT_RETRY_INITIAL_INTERVAL, "CONNECT_RETRY_INITIAL_INTERVAL"); err != nil {
+		return err
+	}
+
+	if err := convertToDuration(&CONNECT_RETRY_MAX_INTERVAL, "CONNECT_RETRY_MAX_INTERVAL"); err != nil {
+		return err
+	}
+
+	if err := convertToDuration(&CONNECT_RETRY_MAX_WAIT, "CONNECT_RETRY_MAX_WAIT"); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -93,3 +157,13 @@ func convertBoolEnv(env *bool, envName string) error {
 	}
 	return nil
 }
+
+func convertToDuration(env *time.Duration, envName string) error {
+	if envString := os.Getenv(envName); envString != "" {
+		var err error
+		if *env, err = time.ParseDuration(envString); err != nil {
+			return fmt.Errorf(errorDurationParse, envName, envString, err)
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package env
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestConvertToInt_ValidValue(t *testing.T) {
@@ -90,10 +91,113 @@ func TestConvertBoolEnv_InvalidValue(t *testing.T) {
 	}
 }
 
+func TestConvertToDuration_ValidValue(t *testing.T) {
+	t.Setenv("TEST_DURATION_VALID", "90s")
+
+	var result time.Duration
+	err := convertToDuration(&result, "TEST_DURATION_VALID")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result != 90*time.Second {
+		t.Fatalf("expected 90s, got %v", result)
+	}
+}
+
+func TestConvertToDuration_EmptyValue(t *testing.T) {
+	os.Unsetenv("TEST_DURATION_EMPTY")
+
+	result := time.Minute
+	err := convertToDuration(&result, "TEST_DURATION_EMPTY")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result != time.Minute {
+		t.Fatalf("expected 1m (unchanged), got %v", result)
+	}
+}
+
+func TestConvertToDuration_InvalidValue(t *testing.T) {
+	t.Setenv("TEST_DURATION_INVALID", "ten minutes")
+
+	var result time.Duration
+	err := convertToDuration(&result, "TEST_DURATION_INVALID")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestValidateAndLoad_PoolEnvVars(t *testing.T) {
+	t.Setenv("SQL_DB_MAX_OPEN_CONNS", "20")
+	t.Setenv("SQL_DB_MAX_IDLE_CONNS", "10")
+	t.Setenv("SQL_DB_CONN_MAX_LIFETIME", "1h")
+	t.Setenv("SQL_DB_CONN_MAX_IDLE_TIME", "2m")
+
+	err := validateAndLoad()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if SQL_DB_MAX_OPEN_CONNS != 20 {
+		t.Fatalf("expected SQL_DB_MAX_OPEN_CONNS=20, got %d", SQL_DB_MAX_OPEN_CONNS)
+	}
+	if SQL_DB_MAX_IDLE_CONNS != 10 {
+		t.Fatalf("expected SQL_DB_MAX_IDLE_CONNS=10, got %d", SQL_DB_MAX_IDLE_CONNS)
+	}
+	if SQL_DB_CONN_MAX_LIFETIME != time.Hour {
+		t.Fatalf("expected SQL_DB_CONN_MAX_LIFETIME=1h, got %v", SQL_DB_CONN_MAX_LIFETIME)
+	}
+	if SQL_DB_CONN_MAX_IDLE_TIME != 2*time.Minute {
+		t.Fatalf("expected SQL_DB_CONN_MAX_IDLE_TIME=2m, got %v", SQL_DB_CONN_MAX_IDLE_TIME)
+	}
+}
+
+func TestValidateAndLoad_InvalidMigrationLockWait(t *testing.T) {
+	t.Setenv("SQL_DB_MIGRATION_LOCK_WAIT", "soon")
+
+	err := validateAndLoad()
+	if err == nil {
+		t.Fatal("expected error for invalid SQL_DB_MIGRATION_LOCK_WAIT, got nil")
+	}
+}
+
+func TestValidateAndLoad_InvalidConnMaxLifetime(t *testing.T) {
+	t.Setenv("SQL_DB_CONN_MAX_LIFETIME", "forever")
+
+	err := validateAndLoad()
+	if err == nil {
+		t.Fatal("expected error for invalid SQL_DB_CONN_MAX_LIFETIME, got nil")
+	}
+}
+
+func TestValidateAndLoad_ConnectRetryEnvVars(t *testing.T) {
+	t.Setenv("CONNECT_RETRY_MAX_ATTEMPTS", "5")
+	t.Setenv("CONNECT_RETRY_INITIAL_INTERVAL", "250ms")
+	t.Setenv("CONNECT_RETRY_MAX_INTERVAL", "5s")
+	t.Setenv("CONNECT_RETRY_MAX_WAIT", "2m")
+
+	err := validateAndLoad()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if CONNECT_RETRY_MAX_ATTEMPTS != 5 {
+		t.Fatalf("expected CONNECT_RETRY_MAX_ATTEMPTS=5, got %d", CONNECT_RETRY_MAX_ATTEMPTS)
+	}
+	if CONNECT_RETRY_INITIAL_INTERVAL != 250*time.Millisecond {
+		t.Fatalf("expected CONNECT_RETRY_INITIAL_INTERVAL=250ms, got %v", CONNECT_RETRY_INITIAL_INTERVAL)
+	}
+	if CONNECT_RETRY_MAX_INTERVAL != 5*time.Second {
+		t.Fatalf("expected CONNECT_RETRY_MAX_INTERVAL=5s, got %v", CONNECT_RETRY_MAX_INTERVAL)
+	}
+	if CONNECT_RETRY_MAX_WAIT != 2*time.Minute {
+		t.Fatalf("expected CONNECT_RETRY_MAX_WAIT=2m, got %v", CONNECT_RETRY_MAX_WAIT)
+	}
+}
+
 func TestValidateAndLoad_ValidEnvVars(t *testing.T) {
 	t.Setenv("SERVER_PORT", "9090")
 	t.Setenv("SQL_DB_PORT", "5433")
 	t.Setenv("RABBITMQ_PORT", "5673")
+	t.Setenv("RABBITMQ_PUBLISH_BUFFER", "500")
 	t.Setenv("SQL_DB_EXEC_MIGRATION", "true")
 
 	err := validateAndLoad()
@@ -109,6 +213,9 @@ func TestValidateAndLoad_ValidEnvVars(t *testing.T) {
 	if RABBITMQ_PORT != 5673 {
 		t.Fatalf("expected RABBITMQ_PORT=5673, got %d", RABBITMQ_PORT)
 	}
+	if RABBITMQ_PUBLISH_BUFFER != 500 {
+		t.Fatalf("expected RABBITMQ_PUBLISH_BUFFER=500, got %d", RABBITMQ_PUBLISH_BUFFER)
+	}
 	if !SQL_DB_EXEC_MIGRATION {
 		t.Fatal("expected SQL_DB_EXEC_MIGRATION=true, got false")
 	}
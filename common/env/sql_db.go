@@ -0,0 +1,127 @@
+package env
+
+import (
+	"os"
+	"time"
+)
+
+// Defaults of the SQL_DB_* variables, shared by the unprefixed variables and
+// LoadSqlDB.
+const (
+	defaultSqlDBDriver             string        = "postgres"
+	defaultSqlDBPort               int           = 5432
+	defaultSqlDBMaxOpenConns       int           = 10
+	defaultSqlDBMaxIdleConns       int           = 5
+	defaultSqlDBConnMaxLifetime    time.Duration = 30 * time.Minute
+	defaultSqlDBConnMaxIdleTime    time.Duration = 5 * time.Minute
+	defaultSqlDBReplicaHealthCheck time.Duration = 10 * time.Second
+	defaultSqlDBMigrationLockMode  string        = "wait"
+	defaultSqlDBMigrationLockWait  time.Duration = time.Minute
+)
+
+type SqlDB struct {
+	Driver             string
+	Url                string
+	Port               int
+	PortSet            bool
+	Name               string
+	Username           string
+	Password           string
+	SslMode            string
+	ExecMigration      bool
+	MigrationSourceUrl string
+	MaxOpenConns       int
+	MaxIdleConns       int
+	MaxIdleConnsSet    bool
+	ConnMaxLifetime    time.Duration
+	ConnMaxIdleTime    time.Duration
+	ReplicaUrls        string
+	ReplicaHealthCheck time.Duration
+	MigrationLockMode  string
+	MigrationLockWait  time.Duration
+}
+
+func DefaultSqlDB() SqlDB {
+	return SqlDB{
+		Driver:             SQL_DB_DRIVER,
+		Url:                SQL_DB_URL,
+		Port:               SQL_DB_PORT,
+		PortSet:            os.Getenv("SQL_DB_PORT") != "",
+		Name:               SQL_DB_NAME,
+		Username:           SQL_DB_USERNAME,
+		Password:           SQL_DB_PASSWORD,
+		SslMode:            SQL_DB_SSL_MODE,
+		ExecMigration:      SQL_DB_EXEC_MIGRATION,
+		MigrationSourceUrl: SQL_DB_MIGRATION_SOURCE_URL,
+		MaxOpenConns:       SQL_DB_MAX_OPEN_CONNS,
+		MaxIdleConns:       SQL_DB_MAX_IDLE_CONNS,
+		MaxIdleConnsSet:    os.Getenv("SQL_DB_MAX_IDLE_CONNS") != "",
+		ConnMaxLifetime:    SQL_DB_CONN_MAX_LIFETIME,
+		ConnMaxIdleTime:    SQL_DB_CONN_MAX_IDLE_TIME,
+		ReplicaUrls:        SQL_DB_REPLICA_URLS,
+		ReplicaHealthCheck: SQL_DB_REPLICA_HEALTH_CHECK,
+		MigrationLockMode:  SQL_DB_MIGRATION_LOCK_MODE,
+		MigrationLockWait:  SQL_DB_MIGRATION_LOCK_WAIT,
+	}
+}
+
+func LoadSqlDB(prefix string) (SqlDB, error) {
+	cfg := SqlDB{
+		Driver:             defaultSqlDBDriver,
+		Port:               defaultSqlDBPort,
+		MaxOpenConns:       defaultSqlDBMaxOpenConns,
+		MaxIdleConns:       defaultSqlDBMaxIdleConns,
+		ConnMaxLifetime:    defaultSqlDBConnMaxLifetime,
+		ConnMaxIdleTime:    defaultSqlDBConnMaxIdleTime,
+		ReplicaHealthCheck: defaultSqlDBReplicaHealthCheck,
+		MigrationLockMode:  defaultSqlDBMigrationLockMode,
+		MigrationLockWait:  defaultSqlDBMigrationLockWait,
+	}
+
+	name := func(envName string) string {
+		return prefix + "_" + envName
+	}
+
+	if err := convertToInt(&cfg.Port, name("SQL_DB_PORT")); err != nil {
+		return cfg, err
+	}
+	if err := convertBoolEnv(&cfg.ExecMigration, name("SQL_DB_EXEC_MIGRATION")); err != nil {
+		return cfg, err
+	}
+	if err := convertToInt(&cfg.MaxOpenConns, name("SQL_DB_MAX_OPEN_CONNS")); err != nil {
+		return cfg, err
+	}
+	if err := convertToInt(&cfg.MaxIdleConns, name("SQL_DB_MAX_IDLE_CONNS")); err != nil {
+		return cfg, err
+	}
+	if err := convertToDuration(&cfg.ConnMaxLifetime, name("SQL_DB_CONN_MAX_LIFETIME")); err != nil {
+		return cfg, err
+	}
+	if err := convertToDuration(&cfg.ConnMaxIdleTime, name("SQL_DB_CONN_MAX_IDLE_TIME")); err != nil {
+		return cfg, err
+	}
+	if err := convertToDuration(&cfg.ReplicaHealthCheck, name("SQL_DB_REPLICA_HEALTH_CHECK")); err != nil {
+		return cfg, err
+	}
+	if err := convertToDuration(&cfg.MigrationLockWait, name("SQL_DB_MIGRATION_LOCK_WAIT")); err != nil {
+		return cfg, err
+	}
+
+	if driver := os.Getenv(name("SQL_DB_DRIVER")); driver != "" {
+		cfg.Driver = driver
+	}
+	cfg.PortSet = os.Getenv(name("SQL_DB_PORT")) != ""
+	cfg.MaxIdleConnsSet = os.Getenv(name("SQL_DB_MAX_IDLE_CONNS")) != ""
+	cfg.Url = os.Getenv(name("SQL_DB_URL"))
+	cfg.Name = os.Getenv(name("SQL_DB_NAME"))
+	cfg.Username = os.Getenv(name("SQL_DB_USERNAME"))
+	cfg.Password = os.Getenv(name("SQL_DB_PASSWORD"))
+	cfg.SslMode = os.Getenv(name("SQL_DB_SSL_MODE"))
+	cfg.MigrationSourceUrl = os.Getenv(name("SQL_DB_MIGRATION_SOURCE_URL"))
+	cfg.ReplicaUrls = os.Getenv(name("SQL_DB_REPLICA_URLS"))
+	if lockMode := os.Getenv(name("SQL_DB_MIGRATION_LOCK_MODE")); lockMode != "" {
+		cfg.MigrationLockMode = lockMode
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,69 @@
+package env
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadSqlDB_Prefixed(t *testing.T) {
+	t.Setenv("REPORTING_SQL_DB_URL", "reporting-host")
+	t.Setenv("REPORTING_SQL_DB_PORT", "5433")
+	t.Setenv("REPORTING_SQL_DB_NAME", "reports")
+	t.Setenv("REPORTING_SQL_DB_USERNAME", "reader")
+	t.Setenv("REPORTING_SQL_DB_PASSWORD", "secret")
+	t.Setenv("REPORTING_SQL_DB_SSL_MODE", "require")
+	t.Setenv("REPORTING_SQL_DB_EXEC_MIGRATION", "true")
+	t.Setenv("REPORTING_SQL_DB_MIGRATION_SOURCE_URL", "/migrations/reporting")
+	t.Setenv("REPORTING_SQL_DB_MAX_OPEN_CONNS", "4")
+	t.Setenv("REPORTING_SQL_DB_MAX_IDLE_CONNS", "2")
+
+	cfg, err := LoadSqlDB("REPORTING")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Url != "reporting-host" || cfg.Port != 5433 || !cfg.PortSet || cfg.Name != "reports" {
+		t.Fatalf("unexpected connection config: %+v", cfg)
+	}
+	if cfg.Username != "reader" || cfg.Password != "secret" || cfg.SslMode != "require" {
+		t.Fatalf("unexpected credentials config: %+v", cfg)
+	}
+	if !cfg.ExecMigration || cfg.MigrationSourceUrl != "/migrations/reporting" {
+		t.Fatalf("unexpected migration config: %+v", cfg)
+	}
+	if cfg.MaxOpenConns != 4 {
+		t.Fatalf("expected MaxOpenConns=4, got %d", cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns != 2 || !cfg.MaxIdleConnsSet {
+		t.Fatalf("expected explicit MaxIdleConns=2, got %d (set: %v)", cfg.MaxIdleConns, cfg.MaxIdleConnsSet)
+	}
+}
+
+func TestLoadSqlDB_Defaults(t *testing.T) {
+	cfg, err := LoadSqlDB("EMPTY_PREFIX")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Driver != "postgres" {
+		t.Fatalf("expected Driver=postgres, got %s", cfg.Driver)
+	}
+	if cfg.Port != 5432 {
+		t.Fatalf("expected Port=5432, got %d", cfg.Port)
+	}
+	if cfg.PortSet || cfg.MaxIdleConnsSet {
+		t.Fatal("expected Port and MaxIdleConns not to be marked as set")
+	}
+	if cfg.ConnMaxLifetime != 30*time.Minute {
+		t.Fatalf("expected ConnMaxLifetime=30m, got %v", cfg.ConnMaxLifetime)
+	}
+	if cfg.MigrationLockMode != "wait" || cfg.MigrationLockWait != time.Minute {
+		t.Fatalf("expected migration lock wait/1m, got %s/%v", cfg.MigrationLockMode, cfg.MigrationLockWait)
+	}
+}
+
+func TestLoadSqlDB_InvalidPort(t *testing.T) {
+	t.Setenv("BROKEN_SQL_DB_PORT", "invalid")
+
+	if _, err := LoadSqlDB("BROKEN"); err == nil {
+		t.Fatal("expected error for invalid prefixed port, got nil")
+	}
+}
@@ -10,15 +10,13 @@ import (
 var once sync.Once
 var singleInstance *sync.WaitGroup
 
+// GetWaitGroup is called concurrently by the consumer workers, so the
+// instance is only read after once.Do.
 func GetWaitGroup() *sync.WaitGroup {
-	if singleInstance == nil {
-		once.Do(func() {
-			logging.Debug("Creating single WaitGroup instance now.")
-			singleInstance = &sync.WaitGroup{}
-		})
-	} else {
-		logging.Debug("Single WaitGroup instance already created.")
-	}
+	once.Do(func() {
+		logging.Debug("Creating single WaitGroup instance now.")
+		singleInstance = &sync.WaitGroup{}
+	})
 
 	return singleInstance
 }
@@ -28,6 +28,23 @@ func TestGetWaitGroup_NotNil(t *testing.T) {
 	}
 }
 
+func TestGetWaitGroup_Concurrent(t *testing.T) {
+	once = sync.Once{}
+	singleInstance = nil
+
+	instances := make(chan *sync.WaitGroup, 10)
+	for range 10 {
+		go func() { instances <- GetWaitGroup() }()
+	}
+
+	first := <-instances
+	for range 9 {
+		if wg := <-instances; wg == nil || wg != first {
+			t.Fatal("expected every caller to get the same non-nil WaitGroup")
+		}
+	}
+}
+
 func TestWaitRunningTimeout_NoWork_ReturnsFalse(t *testing.T) {
 	once = sync.Once{}
 	singleInstance = nil
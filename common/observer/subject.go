@@ -54,11 +54,14 @@ func (s *service) attach(observer Observer) error {
 	return nil
 }
 
+// notify closes the observers in reverse attach order, so a service stops
+// before the ones it was started on top of (e.g. the outbox relay before the
+// database and messaging it uses).
 func (s *service) notify() {
 	s.mx.Lock()
 	defer s.mx.Unlock()
 	s.isShuttingDown = true
-	for _, observer := range s.observers {
-		observer.Close()
+	for i := len(s.observers) - 1; i >= 0; i-- {
+		s.observers[i].Close()
 	}
 }
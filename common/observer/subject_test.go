@@ -1,6 +1,7 @@
 package observer
 
 import (
+	"reflect"
 	"sync"
 	"testing"
 )
@@ -80,6 +81,31 @@ func TestNotify_ClosesAllObservers(t *testing.T) {
 	}
 }
 
+type orderedObserver struct {
+	name   string
+	closed *[]string
+}
+
+func (o orderedObserver) Close() {
+	*o.closed = append(*o.closed, o.name)
+}
+
+func TestNotify_ClosesInReverseAttachOrder(t *testing.T) {
+	svc := newTestService()
+	var closed []string
+
+	for _, name := range []string{"database", "messaging", "outbox"} {
+		svc.attach(orderedObserver{name: name, closed: &closed})
+	}
+
+	svc.notify()
+
+	expected := []string{"outbox", "messaging", "database"}
+	if !reflect.DeepEqual(closed, expected) {
+		t.Fatalf("expected close order %v, got %v", expected, closed)
+	}
+}
+
 func TestNotify_SetsShuttingDown(t *testing.T) {
 	svc := newTestService()
 	svc.notify()
@@ -0,0 +1,132 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/common/env"
+	"github.com/sdkopen/sdkopen-go/logging"
+)
+
+const (
+	defaultMultiplier float64 = 2
+	defaultJitter     float64 = 0.2
+
+	attemptFailedMsg      string = "%s: attempt %d failed: %v, retrying in %s"
+	attemptSucceededMsg   string = "%s: succeeded after %d attempts"
+	maxAttemptsErrorMsg   string = "%s: giving up after %d attempts: %w"
+	maxWaitErrorMsg       string = "%s: giving up after %d attempts in %s: %w"
+	contextDoneErrorMsg   string = "%s: giving up after %d attempts, context done: %w"
+	invalidConfigErrorMsg string = "%s: invalid retry configuration, initial interval must be greater than zero"
+)
+
+type Config struct {
+	MaxAttempts     int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	MaxWait         time.Duration
+	Multiplier      float64
+	Jitter          float64
+}
+
+// permanentError stops Do without further attempts.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks err as not retryable. Do returns the wrapped error as is.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err}
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MaxAttempts:     env.CONNECT_RETRY_MAX_ATTEMPTS,
+		InitialInterval: env.CONNECT_RETRY_INITIAL_INTERVAL,
+		MaxInterval:     env.CONNECT_RETRY_MAX_INTERVAL,
+		MaxWait:         env.CONNECT_RETRY_MAX_WAIT,
+		Multiplier:      defaultMultiplier,
+		Jitter:          defaultJitter,
+	}
+}
+
+func Do(ctx context.Context, operation string, cfg Config, fn func() error) error {
+	if cfg.InitialInterval <= 0 && cfg.MaxAttempts != 1 {
+		return fmt.Errorf(invalidConfigErrorMsg, operation)
+	}
+
+	start := time.Now()
+	interval := cfg.InitialInterval
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			if attempt > 1 {
+				logging.Info(attemptSucceededMsg, operation, attempt)
+			}
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+
+		if cfg.MaxAttempts > 0 && attempt >= cfg.MaxAttempts {
+			return fmt.Errorf(maxAttemptsErrorMsg, operation, attempt, err)
+		}
+
+		delay := cfg.jitter(interval)
+		if elapsed := time.Since(start); cfg.MaxWait > 0 && elapsed+delay > cfg.MaxWait {
+			return fmt.Errorf(maxWaitErrorMsg, operation, attempt, elapsed.Round(time.Millisecond), err)
+		}
+
+		logging.Warn(attemptFailedMsg, operation, attempt, err, delay.Round(time.Millisecond))
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf(contextDoneErrorMsg, operation, attempt, err)
+		case <-time.After(delay):
+		}
+
+		interval = cfg.next(interval)
+	}
+}
+
+func (c Config) next(interval time.Duration) time.Duration {
+	multiplier := c.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	next := time.Duration(float64(interval) * multiplier)
+	if c.MaxInterval > 0 && next > c.MaxInterval {
+		return c.MaxInterval
+	}
+
+	return next
+}
+
+func (c Config) jitter(interval time.Duration) time.Duration {
+	if c.Jitter <= 0 {
+		return interval
+	}
+
+	jitter := min(c.Jitter, 1)
+	return time.Duration(float64(interval) * (1 - jitter*rand.Float64()))
+}
@@ -0,0 +1,180 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/common/env"
+)
+
+func testConfig() Config {
+	return Config{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     5 * time.Millisecond,
+		Multiplier:      2,
+	}
+}
+
+func TestDo_SucceedsFirstAttempt(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), "test", testConfig(), func() error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestDo_RetriesUntilSuccess(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), "test", testConfig(), func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("not ready")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestDo_MaxAttempts(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxAttempts = 4
+	expectedErr := errors.New("connection refused")
+
+	calls := 0
+	err := Do(context.Background(), "test", cfg, func() error {
+		calls++
+		return expectedErr
+	})
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("expected wrapped %v, got %v", expectedErr, err)
+	}
+	if calls != 4 {
+		t.Fatalf("expected 4 calls, got %d", calls)
+	}
+}
+
+func TestDo_PermanentError(t *testing.T) {
+	expectedErr := errors.New("syntax error")
+
+	calls := 0
+	err := Do(context.Background(), "test", testConfig(), func() error {
+		calls++
+		return Permanent(expectedErr)
+	})
+	if err != expectedErr {
+		t.Fatalf("expected unwrapped %v, got %v", expectedErr, err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+	if Permanent(nil) != nil {
+		t.Fatal("expected nil error to stay nil")
+	}
+}
+
+func TestDo_MaxWait(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxWait = 20 * time.Millisecond
+
+	start := time.Now()
+	err := Do(context.Background(), "test", cfg, func() error {
+		return errors.New("connection refused")
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected to give up around max wait, took %v", elapsed)
+	}
+}
+
+func TestDo_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := Do(ctx, "test", testConfig(), func() error {
+		calls++
+		return errors.New("connection refused")
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestDo_InvalidConfig(t *testing.T) {
+	err := Do(context.Background(), "test", Config{}, func() error { return nil })
+	if err == nil {
+		t.Fatal("expected error for zero initial interval, got nil")
+	}
+}
+
+func TestDo_SingleAttemptWithoutInterval(t *testing.T) {
+	expected := errors.New("boom")
+
+	calls := 0
+	err := Do(context.Background(), "test", Config{MaxAttempts: 1}, func() error {
+		calls++
+		return expected
+	})
+	if !errors.Is(err, expected) {
+		t.Fatalf("expected %v, got %v", expected, err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestConfig_Next(t *testing.T) {
+	cfg := Config{Multiplier: 2, MaxInterval: 300 * time.Millisecond}
+
+	if next := cfg.next(100 * time.Millisecond); next != 200*time.Millisecond {
+		t.Fatalf("expected 200ms, got %v", next)
+	}
+	if next := cfg.next(200 * time.Millisecond); next != 300*time.Millisecond {
+		t.Fatalf("expected capped 300ms, got %v", next)
+	}
+}
+
+func TestConfig_Jitter(t *testing.T) {
+	cfg := Config{Jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		delay := cfg.jitter(100 * time.Millisecond)
+		if delay < 50*time.Millisecond || delay > 100*time.Millisecond {
+			t.Fatalf("expected delay between 50ms and 100ms, got %v", delay)
+		}
+	}
+}
+
+func TestDefaultConfig(t *testing.T) {
+	env.CONNECT_RETRY_MAX_ATTEMPTS = 3
+	env.CONNECT_RETRY_INITIAL_INTERVAL = time.Second
+	env.CONNECT_RETRY_MAX_INTERVAL = 5 * time.Second
+	env.CONNECT_RETRY_MAX_WAIT = time.Minute
+
+	cfg := DefaultConfig()
+
+	if cfg.MaxAttempts != 3 || cfg.InitialInterval != time.Second || cfg.MaxInterval != 5*time.Second || cfg.MaxWait != time.Minute {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	if cfg.Multiplier != defaultMultiplier || cfg.Jitter != defaultJitter {
+		t.Fatalf("unexpected backoff settings: %+v", cfg)
+	}
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sdkopen/sdkopen-go/logging"
+)
+
+const (
+	defaultBatchSize int = 1000
+
+	batchSizeErrorMsg    string = "batch size must be greater than zero"
+	batchCopyErrorMsg    string = "copy in is not supported by the %s dialect"
+	batchFailureErrorMsg string = "batch %d (rows %d-%d): %w"
+	batchFailureLogMsg   string = "Batch %d failed and was rolled back: %v"
+)
+
+type BatchProgress struct {
+	Batch     int
+	Size      int
+	Processed int
+	Total     int
+	Err       error
+}
+
+type BatchFailure struct {
+	Batch  int
+	Offset int
+	Size   int
+	Err    error
+}
+
+type BatchResult struct {
+	Total     int
+	Succeeded int
+	Failures  []BatchFailure
+}
+
+type Batch struct {
+	ctx        context.Context
+	query      string
+	copy       bool
+	rows       [][]any
+	size       int
+	onProgress func(BatchProgress)
+	instance   string
+}
+
+func NewBatch(ctx context.Context, query string) *Batch {
+	return &Batch{ctx: ctx, query: query, size: defaultBatchSize}
+}
+
+func NewCopyIn(ctx context.Context, table string, columns ...string) *Batch {
+	return &Batch{ctx: ctx, query: pq.CopyIn(table, columns...), copy: true, size: defaultBatchSize}
+}
+
+func (b *Batch) Add(args ...any) *Batch {
+	b.rows = append(b.rows, args)
+	return b
+}
+
+func (b *Batch) On(instance string) *Batch {
+	b.instance = instance
+	return b
+}
+
+func (b *Batch) WithSize(size int) *Batch {
+	b.size = size
+	return b
+}
+
+func (b *Batch) OnProgress(fn func(BatchProgress)) *Batch {
+	b.onProgress = fn
+	return b
+}
+
+func (b *Batch) Execute() (BatchResult, error) {
+	return b.ExecuteInInstance(Instance(b.instance))
+}
+
+func (b *Batch) ExecuteInInstance(instance *sql.DB) (BatchResult, error) {
+	result := BatchResult{Total: len(b.rows)}
+
+	if err := NewStatement(b.ctx, b.query).validate(instance); err != nil {
+		return result, err
+	}
+	if b.size <= 0 {
+		return result, errors.New(batchSizeErrorMsg)
+	}
+	if dialect := dialectOf(instance); b.copy && dialect != PostgresDialect {
+		return result, fmt.Errorf(batchCopyErrorMsg, dialect.Name())
+	}
+
+	var errs []error
+	for batch, offset := 0, 0; offset < len(b.rows); batch, offset = batch+1, offset+b.size {
+		chunk := b.rows[offset:min(offset+b.size, len(b.rows))]
+
+		err := WithTransactionInInstance(b.ctx, instance, func(ctx context.Context) error {
+			return b.executeChunk(ctx, instance, chunk)
+		})
+		if err != nil {
+			logging.Error(batchFailureLogMsg, batch, err)
+			result.Failures = append(result.Failures, BatchFailure{batch, offset, len(chunk), err})
+			errs = append(errs, fmt.Errorf(batchFailureErrorMsg, batch, offset, offset+len(chunk)-1, err))
+		} else {
+			result.Succeeded += len(chunk)
+		}
+
+		if b.onProgress != nil {
+			b.onProgress(BatchProgress{
+				Batch:     batch,
+				Size:      len(chunk),
+				Processed: offset + len(chunk),
+				Total:     len(b.rows),
+				Err:       err,
+			})
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+func (b *Batch) executeChunk(ctx context.Context, instance *sql.DB, chunk [][]any) error {
+	stmt, err := NewStatement(ctx, b.query).createStatement(instance)
+	if err != nil {
+		return err
+	}
+	defer closer(stmt)
+
+	for _, args := range chunk {
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return err
+		}
+	}
+
+	if b.copy {
+		_, err = stmt.ExecContext(ctx)
+	}
+
+	return err
+}
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBatch_ExecuteInInstance_ReusesStatementPerChunk(t *testing.T) {
+	db, drv := newFakeDB(t, nil)
+	var progress []BatchProgress
+
+	batch := NewBatch(context.Background(), "INSERT INTO users (name) VALUES ($1)").
+		WithSize(2).
+		OnProgress(func(p BatchProgress) { progress = append(progress, p) })
+	for _, name := range []string{"a", "b", "c"} {
+		batch.Add(name)
+	}
+
+	result, err := batch.ExecuteInInstance(db)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Total != 3 || result.Succeeded != 3 || len(result.Failures) != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	query := "INSERT INTO users (name) VALUES ($1)"
+	expected := []string{"BEGIN", query, query, "COMMIT", "BEGIN", query, "COMMIT"}
+	if events := drv.Events(); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+
+	if len(progress) != 2 {
+		t.Fatalf("expected 2 progress reports, got %d", len(progress))
+	}
+	if progress[1].Processed != 3 || progress[1].Total != 3 || progress[1].Size != 1 {
+		t.Fatalf("unexpected last progress: %+v", progress[1])
+	}
+}
+
+func TestBatch_ExecuteInInstance_PartialFailure(t *testing.T) {
+	db, drv := newFakeDB(t, func(_ string, args []driver.Value) fakeResponse {
+		if len(args) > 0 && args[0] == "bad" {
+			return fakeResponse{err: errors.New("invalid row")}
+		}
+		return fakeResponse{}
+	})
+
+	result, err := NewBatch(context.Background(), "INSERT INTO users (name) VALUES ($1)").
+		WithSize(2).
+		Add("a").Add("b").
+		Add("c").Add("bad").
+		Add("e").
+		ExecuteInInstance(db)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if result.Succeeded != 3 {
+		t.Fatalf("expected 3 succeeded rows, got %d", result.Succeeded)
+	}
+	if len(result.Failures) != 1 {
+		t.Fatalf("expected 1 failure, got %d", len(result.Failures))
+	}
+	if failure := result.Failures[0]; failure.Batch != 1 || failure.Offset != 2 || failure.Size != 2 {
+		t.Fatalf("unexpected failure: %+v", failure)
+	}
+
+	rollbacks := 0
+	for _, event := range drv.Events() {
+		if event == "ROLLBACK" {
+			rollbacks++
+		}
+	}
+	if rollbacks != 1 {
+		t.Fatalf("expected 1 rollback, got %d", rollbacks)
+	}
+}
+
+func TestBatch_ExecuteInInstance_InvalidSize(t *testing.T) {
+	db, _ := newFakeDB(t, nil)
+
+	_, err := NewBatch(context.Background(), "INSERT INTO users (name) VALUES ($1)").WithSize(0).Add("a").ExecuteInInstance(db)
+	if err == nil || err.Error() != batchSizeErrorMsg {
+		t.Fatalf("expected '%s', got %v", batchSizeErrorMsg, err)
+	}
+}
+
+func TestBatch_ExecuteInInstance_NilInstance(t *testing.T) {
+	_, err := NewBatch(context.Background(), "INSERT INTO users (name) VALUES ($1)").Add("a").ExecuteInInstance(nil)
+	if err == nil || err.Error() != dbNotInitializedErrorMsg {
+		t.Fatalf("expected '%s', got %v", dbNotInitializedErrorMsg, err)
+	}
+}
+
+func TestNewCopyIn_FlushesEachChunk(t *testing.T) {
+	db, drv := newFakeDB(t, nil)
+
+	result, err := NewCopyIn(context.Background(), "users", "name", "email").
+		Add("Alice", "alice@example.com").
+		Add("Bob", "bob@example.com").
+		ExecuteInInstance(db)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Succeeded != 2 {
+		t.Fatalf("expected 2 succeeded rows, got %d", result.Succeeded)
+	}
+
+	query := `COPY "users" ("name", "email") FROM STDIN`
+	expected := []string{"BEGIN", query, query, query, "COMMIT"}
+	if events := drv.Events(); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+}
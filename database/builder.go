@@ -0,0 +1,593 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	builderNoTableErrorMsg   string = "query builder has no table"
+	builderNoValuesErrorMsg  string = "query builder has no values to write"
+	builderArgsErrorMsg      string = "expression %q has %d placeholders but %d arguments"
+	builderRowSizeErrorMsg   string = "insert row %d has %d values but %d columns"
+	builderReturningErrorMsg string = "%s does not support RETURNING"
+
+	// Without LIMIT, MySQL and SQLite reject OFFSET; these are their
+	// documented "no limit" values.
+	mysqlNoLimit  string = "18446744073709551615"
+	sqliteNoLimit string = "-1"
+)
+
+// Condition is a fragment of a WHERE clause. Placeholders are written as ?
+// and rendered in the dialect of the target instance; ?? emits a literal ?.
+type Condition interface {
+	render(b *bindings) string
+}
+
+type expr struct {
+	sql  string
+	args []any
+}
+
+func Expr(sql string, args ...any) Condition {
+	return expr{sql: sql, args: args}
+}
+
+func Eq(column string, value any) Condition {
+	if value == nil {
+		return IsNull(column)
+	}
+	return expr{sql: column + " = ?", args: []any{value}}
+}
+
+func NotEq(column string, value any) Condition {
+	if value == nil {
+		return NotNull(column)
+	}
+	return expr{sql: column + " <> ?", args: []any{value}}
+}
+
+func Gt(column string, value any) Condition {
+	return expr{sql: column + " > ?", args: []any{value}}
+}
+
+func Gte(column string, value any) Condition {
+	return expr{sql: column + " >= ?", args: []any{value}}
+}
+
+func Lt(column string, value any) Condition {
+	return expr{sql: column + " < ?", args: []any{value}}
+}
+
+func Lte(column string, value any) Condition {
+	return expr{sql: column + " <= ?", args: []any{value}}
+}
+
+func Like(column string, pattern string) Condition {
+	return expr{sql: column + " LIKE ?", args: []any{pattern}}
+}
+
+func IsNull(column string) Condition {
+	return expr{sql: column + " IS NULL"}
+}
+
+func NotNull(column string) Condition {
+	return expr{sql: column + " IS NOT NULL"}
+}
+
+// In accepts a slice or array of values; an empty list never matches.
+func In(column string, values any) Condition {
+	return inList{column: column, values: expandValues(values)}
+}
+
+func NotIn(column string, values any) Condition {
+	return inList{column: column, values: expandValues(values), not: true}
+}
+
+func And(conditions ...Condition) Condition {
+	return group{operator: " AND ", conditions: conditions}
+}
+
+func Or(conditions ...Condition) Condition {
+	return group{operator: " OR ", conditions: conditions}
+}
+
+func (e expr) render(b *bindings) string {
+	var builder strings.Builder
+	next := 0
+	for i := 0; i < len(e.sql); i++ {
+		if e.sql[i] != '?' {
+			builder.WriteByte(e.sql[i])
+			continue
+		}
+		if i+1 < len(e.sql) && e.sql[i+1] == '?' {
+			builder.WriteByte('?')
+			i++
+			continue
+		}
+
+		var arg any
+		if next < len(e.args) {
+			arg = e.args[next]
+		}
+		next++
+		builder.WriteString(b.bind(arg))
+	}
+
+	if next != len(e.args) {
+		b.fail(fmt.Errorf(builderArgsErrorMsg, e.sql, next, len(e.args)))
+	}
+
+	return builder.String()
+}
+
+type inList struct {
+	column string
+	values []any
+	not    bool
+}
+
+func (c inList) render(b *bindings) string {
+	if len(c.values) == 0 {
+		if c.not {
+			return "1 = 1"
+		}
+		return "1 = 0"
+	}
+
+	placeholders := make([]string, len(c.values))
+	for i, value := range c.values {
+		placeholders[i] = b.bind(value)
+	}
+
+	operator := " IN ("
+	if c.not {
+		operator = " NOT IN ("
+	}
+
+	return c.column + operator + strings.Join(placeholders, ", ") + ")"
+}
+
+type group struct {
+	operator   string
+	conditions []Condition
+}
+
+func (g group) render(b *bindings) string {
+	parts := make([]string, 0, len(g.conditions))
+	for _, condition := range g.conditions {
+		if condition == nil {
+			continue
+		}
+		if part := condition.render(b); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return ""
+	case 1:
+		return parts[0]
+	default:
+		return "(" + strings.Join(parts, g.operator) + ")"
+	}
+}
+
+func expandValues(values any) []any {
+	value := reflect.ValueOf(values)
+	if (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) || value.Type().Elem().Kind() == reflect.Uint8 {
+		return []any{values}
+	}
+
+	result := make([]any, value.Len())
+	for i := range result {
+		result[i] = value.Index(i).Interface()
+	}
+
+	return result
+}
+
+type whereClause struct {
+	condition Condition
+}
+
+func (w *whereClause) and(conditions []Condition) {
+	if w.condition != nil {
+		conditions = append([]Condition{w.condition}, conditions...)
+	}
+	w.condition = And(conditions...)
+}
+
+func (w *whereClause) or(conditions []Condition) {
+	if w.condition == nil {
+		w.condition = And(conditions...)
+		return
+	}
+	w.condition = Or(w.condition, And(conditions...))
+}
+
+func (w *whereClause) render(b *bindings, keyword string) string {
+	if w.condition == nil {
+		return ""
+	}
+	if clause := w.condition.render(b); clause != "" {
+		return " " + keyword + " " + clause
+	}
+
+	return ""
+}
+
+type join struct {
+	kind  string
+	table string
+	on    Condition
+}
+
+type SelectBuilder struct {
+	columns  []string
+	table    string
+	joins    []join
+	where    whereClause
+	groupBy  []string
+	having   whereClause
+	orderBy  []string
+	limit    int
+	offset   int
+	lock     string
+	instance string
+}
+
+func Select(columns ...string) *SelectBuilder {
+	return &SelectBuilder{columns: columns}
+}
+
+func (s *SelectBuilder) From(table string) *SelectBuilder {
+	s.table = table
+	return s
+}
+
+func (s *SelectBuilder) Join(table string, on string, args ...any) *SelectBuilder {
+	s.joins = append(s.joins, join{kind: "JOIN", table: table, on: Expr(on, args...)})
+	return s
+}
+
+func (s *SelectBuilder) LeftJoin(table string, on string, args ...any) *SelectBuilder {
+	s.joins = append(s.joins, join{kind: "LEFT JOIN", table: table, on: Expr(on, args...)})
+	return s
+}
+
+// Where adds conditions joined with AND to the current filter.
+func (s *SelectBuilder) Where(conditions ...Condition) *SelectBuilder {
+	s.where.and(conditions)
+	return s
+}
+
+// OrWhere matches rows from the current filter or from all the given conditions.
+func (s *SelectBuilder) OrWhere(conditions ...Condition) *SelectBuilder {
+	s.where.or(conditions)
+	return s
+}
+
+func (s *SelectBuilder) GroupBy(columns ...string) *SelectBuilder {
+	s.groupBy = append(s.groupBy, columns...)
+	return s
+}
+
+func (s *SelectBuilder) Having(conditions ...Condition) *SelectBuilder {
+	s.having.and(conditions)
+	return s
+}
+
+// OrderBy accepts expressions such as "name" or "created_at DESC".
+func (s *SelectBuilder) OrderBy(columns ...string) *SelectBuilder {
+	s.orderBy = append(s.orderBy, columns...)
+	return s
+}
+
+func (s *SelectBuilder) Limit(limit int) *SelectBuilder {
+	s.limit = limit
+	return s
+}
+
+func (s *SelectBuilder) Offset(offset int) *SelectBuilder {
+	s.offset = offset
+	return s
+}
+
+// ForUpdate locks the selected rows until the end of the transaction. SQLite
+// has no row locks and the clause is left out there.
+func (s *SelectBuilder) ForUpdate() *SelectBuilder {
+	s.lock = "FOR UPDATE"
+	return s
+}
+
+// SkipLocked locks the selected rows like ForUpdate, skipping rows already
+// locked by other transactions, so that concurrent workers split the rows.
+func (s *SelectBuilder) SkipLocked() *SelectBuilder {
+	s.lock = "FOR UPDATE SKIP LOCKED"
+	return s
+}
+
+func (s *SelectBuilder) On(instance string) *SelectBuilder {
+	s.instance = instance
+	return s
+}
+
+func (s *SelectBuilder) Build() (string, []any, error) {
+	return s.BuildFor(DialectOf(s.instance))
+}
+
+func (s *SelectBuilder) BuildFor(dialect Dialect) (string, []any, error) {
+	if s.table == "" {
+		return "", nil, errors.New(builderNoTableErrorMsg)
+	}
+
+	b := &bindings{dialect: dialect}
+	columns := "*"
+	if len(s.columns) > 0 {
+		columns = strings.Join(s.columns, ", ")
+	}
+
+	var query strings.Builder
+	query.WriteString("SELECT " + columns + " FROM " + s.table)
+	for _, j := range s.joins {
+		query.WriteString(" " + j.kind + " " + j.table + " ON " + j.on.render(b))
+	}
+	query.WriteString(s.where.render(b, "WHERE"))
+	if len(s.groupBy) > 0 {
+		query.WriteString(" GROUP BY " + strings.Join(s.groupBy, ", "))
+	}
+	query.WriteString(s.having.render(b, "HAVING"))
+	if len(s.orderBy) > 0 {
+		query.WriteString(" ORDER BY " + strings.Join(s.orderBy, ", "))
+	}
+	if s.limit > 0 {
+		query.WriteString(" LIMIT " + strconv.Itoa(s.limit))
+	} else if s.offset > 0 && dialect == MySQLDialect {
+		query.WriteString(" LIMIT " + mysqlNoLimit)
+	} else if s.offset > 0 && dialect == SQLiteDialect {
+		query.WriteString(" LIMIT " + sqliteNoLimit)
+	}
+	if s.offset > 0 {
+		query.WriteString(" OFFSET " + strconv.Itoa(s.offset))
+	}
+	if s.lock != "" && dialect != SQLiteDialect {
+		query.WriteString(" " + s.lock)
+	}
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	return query.String(), b.args, nil
+}
+
+func (s *SelectBuilder) Statement(ctx context.Context) (*Statement, error) {
+	query, args, err := s.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStatement(ctx, query, args...).On(s.instance), nil
+}
+
+type InsertBuilder struct {
+	table     string
+	columns   []string
+	rows      [][]any
+	returning []string
+	instance  string
+}
+
+func InsertInto(table string) *InsertBuilder {
+	return &InsertBuilder{table: table}
+}
+
+func (i *InsertBuilder) Columns(columns ...string) *InsertBuilder {
+	i.columns = columns
+	return i
+}
+
+// Values adds one row, in the same order as Columns.
+func (i *InsertBuilder) Values(values ...any) *InsertBuilder {
+	i.rows = append(i.rows, values)
+	return i
+}
+
+func (i *InsertBuilder) Returning(columns ...string) *InsertBuilder {
+	i.returning = columns
+	return i
+}
+
+func (i *InsertBuilder) On(instance string) *InsertBuilder {
+	i.instance = instance
+	return i
+}
+
+func (i *InsertBuilder) Build() (string, []any, error) {
+	return i.BuildFor(DialectOf(i.instance))
+}
+
+func (i *InsertBuilder) BuildFor(dialect Dialect) (string, []any, error) {
+	if i.table == "" {
+		return "", nil, errors.New(builderNoTableErrorMsg)
+	}
+	if len(i.rows) == 0 {
+		return "", nil, errors.New(builderNoValuesErrorMsg)
+	}
+	if len(i.returning) > 0 && dialect == MySQLDialect {
+		return "", nil, fmt.Errorf(builderReturningErrorMsg, dialect.Name())
+	}
+
+	// Without Columns, every row must match the first one.
+	width := len(i.columns)
+	if width == 0 {
+		width = len(i.rows[0])
+	}
+
+	b := &bindings{dialect: dialect}
+	rows := make([]string, len(i.rows))
+	for r, values := range i.rows {
+		if len(values) != width {
+			return "", nil, fmt.Errorf(builderRowSizeErrorMsg, r+1, len(values), width)
+		}
+		placeholders := make([]string, len(values))
+		for v, value := range values {
+			placeholders[v] = b.bind(value)
+		}
+		rows[r] = "(" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	query := "INSERT INTO " + i.table
+	if len(i.columns) > 0 {
+		query += " (" + strings.Join(i.columns, ", ") + ")"
+	}
+	query += " VALUES " + strings.Join(rows, ", ")
+	if len(i.returning) > 0 {
+		query += " RETURNING " + strings.Join(i.returning, ", ")
+	}
+
+	return query, b.args, nil
+}
+
+func (i *InsertBuilder) Statement(ctx context.Context) (*Statement, error) {
+	query, args, err := i.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStatement(ctx, query, args...).On(i.instance), nil
+}
+
+type assignment struct {
+	column string
+	value  Condition
+}
+
+type UpdateBuilder struct {
+	table       string
+	assignments []assignment
+	where       whereClause
+	instance    string
+}
+
+func Update(table string) *UpdateBuilder {
+	return &UpdateBuilder{table: table}
+}
+
+func (u *UpdateBuilder) Set(column string, value any) *UpdateBuilder {
+	u.assignments = append(u.assignments, assignment{column: column, value: Expr("?", value)})
+	return u
+}
+
+// SetExpr assigns a raw expression, e.g. SetExpr("stock", "stock - ?", 1).
+func (u *UpdateBuilder) SetExpr(column string, sql string, args ...any) *UpdateBuilder {
+	u.assignments = append(u.assignments, assignment{column: column, value: Expr(sql, args...)})
+	return u
+}
+
+func (u *UpdateBuilder) Where(conditions ...Condition) *UpdateBuilder {
+	u.where.and(conditions)
+	return u
+}
+
+func (u *UpdateBuilder) OrWhere(conditions ...Condition) *UpdateBuilder {
+	u.where.or(conditions)
+	return u
+}
+
+func (u *UpdateBuilder) On(instance string) *UpdateBuilder {
+	u.instance = instance
+	return u
+}
+
+func (u *UpdateBuilder) Build() (string, []any, error) {
+	return u.BuildFor(DialectOf(u.instance))
+}
+
+func (u *UpdateBuilder) BuildFor(dialect Dialect) (string, []any, error) {
+	if u.table == "" {
+		return "", nil, errors.New(builderNoTableErrorMsg)
+	}
+	if len(u.assignments) == 0 {
+		return "", nil, errors.New(builderNoValuesErrorMsg)
+	}
+
+	b := &bindings{dialect: dialect}
+	assignments := make([]string, len(u.assignments))
+	for i, a := range u.assignments {
+		assignments[i] = a.column + " = " + a.value.render(b)
+	}
+
+	query := "UPDATE " + u.table + " SET " + strings.Join(assignments, ", ") + u.where.render(b, "WHERE")
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	return query, b.args, nil
+}
+
+func (u *UpdateBuilder) Statement(ctx context.Context) (*Statement, error) {
+	query, args, err := u.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStatement(ctx, query, args...).On(u.instance), nil
+}
+
+type DeleteBuilder struct {
+	table    string
+	where    whereClause
+	instance string
+}
+
+func DeleteFrom(table string) *DeleteBuilder {
+	return &DeleteBuilder{table: table}
+}
+
+func (d *DeleteBuilder) Where(conditions ...Condition) *DeleteBuilder {
+	d.where.and(conditions)
+	return d
+}
+
+func (d *DeleteBuilder) OrWhere(conditions ...Condition) *DeleteBuilder {
+	d.where.or(conditions)
+	return d
+}
+
+func (d *DeleteBuilder) On(instance string) *DeleteBuilder {
+	d.instance = instance
+	return d
+}
+
+func (d *DeleteBuilder) Build() (string, []any, error) {
+	return d.BuildFor(DialectOf(d.instance))
+}
+
+func (d *DeleteBuilder) BuildFor(dialect Dialect) (string, []any, error) {
+	if d.table == "" {
+		return "", nil, errors.New(builderNoTableErrorMsg)
+	}
+
+	b := &bindings{dialect: dialect}
+	query := "DELETE FROM " + d.table + d.where.render(b, "WHERE")
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	return query, b.args, nil
+}
+
+func (d *DeleteBuilder) Statement(ctx context.Context) (*Statement, error) {
+	query, args, err := d.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStatement(ctx, query, args...).On(d.instance), nil
+}
@@ -0,0 +1,203 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func assertBuild(t *testing.T, query string, args []any, err error, expectedQuery string, expectedArgs ...any) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if query != expectedQuery {
+		t.Fatalf("expected query:\n%s\ngot:\n%s", expectedQuery, query)
+	}
+	if len(expectedArgs) == 0 {
+		expectedArgs = nil
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Fatalf("expected args %v, got %v", expectedArgs, args)
+	}
+}
+
+func TestSelectBuilder_Postgres(t *testing.T) {
+	query, args, err := Select("u.id", "u.name").
+		From("users u").
+		LeftJoin("orders o", "o.user_id = u.id AND o.status = ?", "paid").
+		Where(Eq("u.active", true), In("u.role", []string{"admin", "editor"})).
+		Where(Or(Like("u.name", "A%"), Gte("u.age", 18))).
+		OrderBy("u.name", "u.id DESC").
+		Limit(10).
+		Offset(20).
+		BuildFor(PostgresDialect)
+
+	assertBuild(t, query, args, err,
+		"SELECT u.id, u.name FROM users u LEFT JOIN orders o ON o.user_id = u.id AND o.status = $1"+
+			" WHERE ((u.active = $2 AND u.role IN ($3, $4)) AND (u.name LIKE $5 OR u.age >= $6))"+
+			" ORDER BY u.name, u.id DESC LIMIT 10 OFFSET 20",
+		"paid", true, "admin", "editor", "A%", 18)
+}
+
+func TestSelectBuilder_MySQL(t *testing.T) {
+	query, args, err := Select().From("users").Where(Eq("id", 1), IsNull("deleted_at")).BuildFor(MySQLDialect)
+
+	assertBuild(t, query, args, err, "SELECT * FROM users WHERE (id = ? AND deleted_at IS NULL)", 1)
+}
+
+func TestSelectBuilder_OrWhere(t *testing.T) {
+	query, args, err := Select("id").From("users").
+		Where(Eq("status", "active")).
+		OrWhere(Eq("role", "admin"), NotNull("verified_at")).
+		BuildFor(PostgresDialect)
+
+	assertBuild(t, query, args, err,
+		"SELECT id FROM users WHERE (status = $1 OR (role = $2 AND verified_at IS NOT NULL))",
+		"active", "admin")
+}
+
+func TestSelectBuilder_GroupByHaving(t *testing.T) {
+	query, args, err := Select("user_id", "COUNT(*)").From("orders").
+		Where(Gt("total", 10)).
+		GroupBy("user_id").
+		Having(Expr("COUNT(*) > ?", 3)).
+		BuildFor(PostgresDialect)
+
+	assertBuild(t, query, args, err,
+		"SELECT user_id, COUNT(*) FROM orders WHERE total > $1 GROUP BY user_id HAVING COUNT(*) > $2",
+		10, 3)
+}
+
+func TestSelectBuilder_EmptyIn(t *testing.T) {
+	query, args, err := Select("id").From("users").Where(In("id", []int{}), NotIn("id", []int{})).BuildFor(PostgresDialect)
+
+	assertBuild(t, query, args, err, "SELECT id FROM users WHERE (1 = 0 AND 1 = 1)")
+}
+
+func TestSelectBuilder_NoTable(t *testing.T) {
+	if _, _, err := Select("id").BuildFor(PostgresDialect); err == nil {
+		t.Fatal("expected error for missing table, got nil")
+	}
+}
+
+func TestSelectBuilder_SkipLocked(t *testing.T) {
+	builder := Select("id").From("jobs").Where(IsNull("done_at")).OrderBy("id").Limit(10).SkipLocked()
+
+	query, args, err := builder.BuildFor(PostgresDialect)
+	assertBuild(t, query, args, err, "SELECT id FROM jobs WHERE done_at IS NULL ORDER BY id LIMIT 10 FOR UPDATE SKIP LOCKED")
+
+	query, args, err = builder.BuildFor(SQLiteDialect)
+	assertBuild(t, query, args, err, "SELECT id FROM jobs WHERE done_at IS NULL ORDER BY id LIMIT 10")
+
+	query, args, err = Select("id").From("jobs").ForUpdate().BuildFor(MySQLDialect)
+	assertBuild(t, query, args, err, "SELECT id FROM jobs FOR UPDATE")
+}
+
+func TestExpr_EscapedPlaceholder(t *testing.T) {
+	query, args, err := Select("id").From("docs").Where(Expr("tags ?? ? AND owner = ?", "go", 7)).BuildFor(PostgresDialect)
+
+	assertBuild(t, query, args, err, "SELECT id FROM docs WHERE tags ? $1 AND owner = $2", "go", 7)
+}
+
+func TestExpr_ArgCountMismatch(t *testing.T) {
+	if _, _, err := Select("id").From("docs").Where(Expr("owner = ? AND kind = ?", 7)).BuildFor(PostgresDialect); err == nil {
+		t.Fatal("expected error for missing argument, got nil")
+	}
+	if _, _, err := Update("docs").SetExpr("views", "views + 1", 2).BuildFor(PostgresDialect); err == nil {
+		t.Fatal("expected error for extra argument, got nil")
+	}
+	if _, _, err := DeleteFrom("docs").Where(Expr("id = ?")).BuildFor(PostgresDialect); err == nil {
+		t.Fatal("expected error for placeholder without argument, got nil")
+	}
+}
+
+func TestSelectBuilder_OffsetWithoutLimit(t *testing.T) {
+	query, args, err := Select("id").From("users").Offset(20).BuildFor(PostgresDialect)
+	assertBuild(t, query, args, err, "SELECT id FROM users OFFSET 20")
+
+	query, args, err = Select("id").From("users").Offset(20).BuildFor(MySQLDialect)
+	assertBuild(t, query, args, err, "SELECT id FROM users LIMIT 18446744073709551615 OFFSET 20")
+
+	query, args, err = Select("id").From("users").Offset(20).BuildFor(SQLiteDialect)
+	assertBuild(t, query, args, err, "SELECT id FROM users LIMIT -1 OFFSET 20")
+}
+
+func TestEq_NilValue(t *testing.T) {
+	query, args, err := Select("id").From("users").Where(Eq("manager_id", nil), NotEq("email", nil)).BuildFor(PostgresDialect)
+
+	assertBuild(t, query, args, err, "SELECT id FROM users WHERE (manager_id IS NULL AND email IS NOT NULL)")
+}
+
+func TestInsertBuilder(t *testing.T) {
+	query, args, err := InsertInto("users").
+		Columns("name", "email").
+		Values("Alice", "alice@example.com").
+		Values("Bob", "bob@example.com").
+		Returning("id").
+		BuildFor(PostgresDialect)
+
+	assertBuild(t, query, args, err,
+		"INSERT INTO users (name, email) VALUES ($1, $2), ($3, $4) RETURNING id",
+		"Alice", "alice@example.com", "Bob", "bob@example.com")
+
+	if _, _, err = InsertInto("users").Columns("name").BuildFor(PostgresDialect); err == nil {
+		t.Fatal("expected error for insert without values, got nil")
+	}
+}
+
+func TestInsertBuilder_RowSizeMismatch(t *testing.T) {
+	if _, _, err := InsertInto("users").Columns("name", "email").Values("Alice").BuildFor(PostgresDialect); err == nil {
+		t.Fatal("expected error for row with fewer values than columns, got nil")
+	}
+	if _, _, err := InsertInto("users").Values("Alice", "alice@example.com").Values("Bob").BuildFor(SQLiteDialect); err == nil {
+		t.Fatal("expected error for rows of different sizes, got nil")
+	}
+}
+
+func TestInsertBuilder_ReturningOnMySQL(t *testing.T) {
+	_, _, err := InsertInto("users").Columns("name").Values("Alice").Returning("id").BuildFor(MySQLDialect)
+	if err == nil || err.Error() != "mysql does not support RETURNING" {
+		t.Fatalf("expected RETURNING error for mysql, got %v", err)
+	}
+
+	query, args, err := InsertInto("users").Columns("name").Values("Alice").Returning("id").BuildFor(SQLiteDialect)
+	assertBuild(t, query, args, err, "INSERT INTO users (name) VALUES (?) RETURNING id", "Alice")
+}
+
+func TestUpdateBuilder(t *testing.T) {
+	query, args, err := Update("products").
+		Set("name", "Pen").
+		SetExpr("stock", "stock - ?", 2).
+		Where(Eq("id", 5)).
+		BuildFor(MySQLDialect)
+
+	assertBuild(t, query, args, err, "UPDATE products SET name = ?, stock = stock - ? WHERE id = ?", "Pen", 2, 5)
+
+	if _, _, err = Update("products").BuildFor(MySQLDialect); err == nil {
+		t.Fatal("expected error for update without assignments, got nil")
+	}
+}
+
+func TestDeleteBuilder(t *testing.T) {
+	query, args, err := DeleteFrom("sessions").Where(Lt("expires_at", "2024-01-01")).BuildFor(PostgresDialect)
+
+	assertBuild(t, query, args, err, "DELETE FROM sessions WHERE expires_at < $1", "2024-01-01")
+}
+
+func TestSelectBuilder_StatementUsesInstanceDialect(t *testing.T) {
+	db, _ := newFakeDB(t, nil)
+	registerDialect(db, MySQLDialect)
+	t.Cleanup(func() { unregisterDialect(db) })
+	Register("builder-mysql", func() *sql.DB { return db })
+
+	stmt, err := Select("id").From("users").Where(Eq("id", 1)).On("builder-mysql").Statement(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if stmt.query != "SELECT id FROM users WHERE id = ?" || stmt.instance != "builder-mysql" {
+		t.Fatalf("unexpected statement: %s on %s", stmt.query, stmt.instance)
+	}
+}
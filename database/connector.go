@@ -0,0 +1,283 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/common/env"
+	"github.com/sdkopen/sdkopen-go/common/retry"
+	"github.com/sdkopen/sdkopen-go/logging"
+)
+
+const (
+	dbConnectErrorMsg      string = "an error occurred while trying to connect to the %s database: %+v"
+	dbMigrationErrorMsg    string = "An error occurred when validate database migrations: %+v"
+	dbPoolConfigErrorMsg   string = "An error occurred when validate database pool configuration: %+v"
+	dbReplicaUnavailable   string = "database replica %s unavailable at startup, keeping it evicted: %+v"
+	dbReplicaAddressMsg    string = "invalid database replica address %s: %+v"
+	dbReplicaUnsupported   string = "database replicas are not supported by the %s driver, ignoring %d replica(s)"
+	dbUnsupportedDriverMsg string = "unsupported database driver %q, expected postgres, mysql or sqlite"
+)
+
+type Connector interface {
+	Connect() *sql.DB
+}
+
+type connectorConfig struct {
+	pool      PoolConfig
+	idleSet   bool
+	migration migrationConfig
+	replicas  []string
+	replicaHC time.Duration
+	retry     retry.Config
+}
+
+type ConnectorOption func(*connectorConfig)
+
+func WithPoolConfig(pool PoolConfig) ConnectorOption {
+	return func(c *connectorConfig) {
+		c.pool = pool
+		c.idleSet = true
+	}
+}
+
+func WithMaxOpenConns(n int) ConnectorOption {
+	return func(c *connectorConfig) {
+		c.pool.MaxOpenConns = n
+	}
+}
+
+func WithMaxIdleConns(n int) ConnectorOption {
+	return func(c *connectorConfig) {
+		c.pool.MaxIdleConns = n
+		c.idleSet = true
+	}
+}
+
+func WithConnMaxLifetime(d time.Duration) ConnectorOption {
+	return func(c *connectorConfig) {
+		c.pool.ConnMaxLifetime = d
+	}
+}
+
+func WithConnMaxIdleTime(d time.Duration) ConnectorOption {
+	return func(c *connectorConfig) {
+		c.pool.ConnMaxIdleTime = d
+	}
+}
+
+func WithReplicas(addresses ...string) ConnectorOption {
+	return func(c *connectorConfig) {
+		c.replicas = addresses
+	}
+}
+
+func WithReplicaHealthCheck(interval time.Duration) ConnectorOption {
+	return func(c *connectorConfig) {
+		c.replicaHC = interval
+	}
+}
+
+func WithAutoMigration(enabled bool) ConnectorOption {
+	return func(c *connectorConfig) {
+		c.migration.enabled = enabled
+	}
+}
+
+func WithMigrations(opts ...MigrationOption) ConnectorOption {
+	return func(c *connectorConfig) {
+		for _, opt := range opts {
+			opt(&c.migration)
+		}
+	}
+}
+
+func WithConnectRetry(cfg retry.Config) ConnectorOption {
+	return func(c *connectorConfig) {
+		c.retry = cfg
+	}
+}
+
+func newConnectorConfig(cfg env.SqlDB, opts []ConnectorOption) connectorConfig {
+	config := connectorConfig{
+		pool: PoolConfig{
+			MaxOpenConns:    cfg.MaxOpenConns,
+			MaxIdleConns:    cfg.MaxIdleConns,
+			ConnMaxLifetime: cfg.ConnMaxLifetime,
+			ConnMaxIdleTime: cfg.ConnMaxIdleTime,
+		},
+		migration: migrationConfig{
+			enabled:      cfg.ExecMigration,
+			sourceUrl:    cfg.MigrationSourceUrl,
+			databaseName: cfg.Name,
+			lockMode:     MigrationLockMode(cfg.MigrationLockMode),
+			lockWait:     cfg.MigrationLockWait,
+		},
+		idleSet:   cfg.MaxIdleConnsSet,
+		replicas:  splitAddresses(cfg.ReplicaUrls),
+		replicaHC: cfg.ReplicaHealthCheck,
+		retry:     retry.DefaultConfig(),
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	// Only an idle limit that was set explicitly can conflict with the open
+	// limit; the default one follows it.
+	if !config.idleSet {
+		config.pool = config.pool.clampIdle()
+	}
+
+	return config
+}
+
+// connectTarget describes how a concrete connector reaches its database.
+// replicaDSN is nil for drivers without replica support.
+type connectTarget struct {
+	driver     string
+	dialect    Dialect
+	dsn        string
+	address    string
+	replicaDSN func(address string) (string, error)
+	// migrationDSN, when set, opens the connection the migrations run on.
+	migrationDSN string
+}
+
+func (c *connectorConfig) open(target connectTarget) *sql.DB {
+	if err := c.pool.Validate(); err != nil {
+		logging.Fatal(dbPoolConfigErrorMsg, err)
+	}
+
+	db, err := sql.Open(target.driver, target.dsn)
+	if err != nil {
+		logging.Fatal(dbConnectErrorMsg, target.dialect.Name(), err)
+	}
+	c.pool.apply(db)
+	registerDialect(db, target.dialect)
+	if target.migrationDSN != "" {
+		registerMigrationTarget(db, migrationTarget{driver: target.driver, dsn: target.migrationDSN})
+	}
+
+	operation := fmt.Sprintf("connecting to the %s database at %s", target.dialect.Name(), target.address)
+	if err = retry.Do(context.Background(), operation, c.retry, db.Ping); err != nil {
+		logging.Fatal(dbConnectErrorMsg, target.dialect.Name(), err)
+	}
+
+	if err := migration(db, c.migration); err != nil {
+		logging.Fatal(dbMigrationErrorMsg, err)
+	}
+
+	c.connectReplicas(db, target)
+
+	return db
+}
+
+func (c *connectorConfig) connectReplicas(primary *sql.DB, target connectTarget) {
+	if len(c.replicas) == 0 {
+		return
+	}
+	if target.replicaDSN == nil {
+		logging.Warn(dbReplicaUnsupported, target.dialect.Name(), len(c.replicas))
+		return
+	}
+
+	replicas := make([]*replica, 0, len(c.replicas))
+	for _, address := range c.replicas {
+		dsn, err := target.replicaDSN(address)
+		if err != nil {
+			logging.Fatal(dbReplicaAddressMsg, address, err)
+		}
+
+		db, err := sql.Open(target.driver, dsn)
+		if err != nil {
+			logging.Fatal("an error occurred while trying to connect to the %s database replica %s: %+v", target.dialect.Name(), address, err)
+		}
+		c.pool.apply(db)
+
+		r := &replica{name: address, db: db}
+		if err = db.Ping(); err != nil {
+			logging.Warn(dbReplicaUnavailable, address, err)
+		} else {
+			r.healthy.Store(true)
+		}
+		replicas = append(replicas, r)
+	}
+
+	registerReplicas(primary, replicas, c.replicaHC)
+	logging.Info("database connected with %d replica(s)", len(replicas))
+}
+
+func splitAddresses(addresses string) []string {
+	var result []string
+	for _, address := range strings.Split(addresses, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			result = append(result, address)
+		}
+	}
+
+	return result
+}
+
+// splitHostPort parses a "host[:port]" address, falling back to defaultPort.
+func splitHostPort(address string, defaultPort int) (string, int, error) {
+	host, portStr, hasPort := strings.Cut(address, ":")
+	if !hasPort {
+		return host, defaultPort, nil
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return host, port, nil
+}
+
+func newConnector(cfg env.SqlDB, opts []ConnectorOption) (Connector, error) {
+	switch cfg.Driver {
+	case "", "postgres":
+		return newPostgresqlConnector(cfg, opts), nil
+	case "mysql":
+		return newMySQLConnector(cfg, opts), nil
+	case "sqlite":
+		return newSQLiteConnector(cfg, opts), nil
+	default:
+		return nil, fmt.Errorf(dbUnsupportedDriverMsg, cfg.Driver)
+	}
+}
+
+// NewDefaultConnector builds the connector selected by SQL_DB_DRIVER.
+func NewDefaultConnector(opts ...ConnectorOption) (Connector, error) {
+	return newConnector(env.DefaultSqlDB(), opts)
+}
+
+// FromEnv opens the database selected by SQL_DB_DRIVER.
+func FromEnv() *sql.DB {
+	connector, err := NewDefaultConnector()
+	if err != nil {
+		logging.Fatal("%s", err.Error())
+	}
+
+	return connector.Connect()
+}
+
+func FromEnvWithPrefix(prefix string) func() *sql.DB {
+	return func() *sql.DB {
+		cfg, err := env.LoadSqlDB(prefix)
+		if err != nil {
+			logging.Fatal("%s", err.Error())
+		}
+
+		connector, err := newConnector(cfg, nil)
+		if err != nil {
+			logging.Fatal("%s", err.Error())
+		}
+
+		return connector.Connect()
+	}
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sdkopen/sdkopen-go/common/env"
+)
+
+func TestNewConnector_Driver(t *testing.T) {
+	cases := map[string]string{
+		"":         "*database.PostgresqlConnector",
+		"postgres": "*database.PostgresqlConnector",
+		"mysql":    "*database.MySQLConnector",
+		"sqlite":   "*database.SQLiteConnector",
+	}
+
+	for driver, expected := range cases {
+		connector, err := newConnector(env.SqlDB{Driver: driver}, nil)
+		if err != nil {
+			t.Fatalf("expected no error for driver %q, got %v", driver, err)
+		}
+		if got := fmt.Sprintf("%T", connector); got != expected {
+			t.Fatalf("expected %s for driver %q, got %s", expected, driver, got)
+		}
+	}
+}
+
+func TestNewConnector_UnsupportedDriver(t *testing.T) {
+	if _, err := newConnector(env.SqlDB{Driver: "oracle"}, nil); err == nil {
+		t.Fatal("expected error for unsupported driver, got nil")
+	}
+}
+
+func TestSplitHostPort(t *testing.T) {
+	host, port, err := splitHostPort("db-1:6000", 5432)
+	if err != nil || host != "db-1" || port != 6000 {
+		t.Fatalf("unexpected result: %s, %d, %v", host, port, err)
+	}
+
+	host, port, err = splitHostPort("db-2", 5432)
+	if err != nil || host != "db-2" || port != 5432 {
+		t.Fatalf("expected default port, got %s, %d, %v", host, port, err)
+	}
+
+	if _, _, err = splitHostPort("db-3:abc", 5432); err == nil {
+		t.Fatal("expected error for invalid port, got nil")
+	}
+}
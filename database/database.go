@@ -2,23 +2,51 @@ package database
 
 import (
 	"database/sql"
+	"sync"
 
 	"github.com/sdkopen/sdkopen-go/common/observer"
 	"github.com/sdkopen/sdkopen-go/logging"
 )
 
 const (
+	DefaultInstance string = "default"
+
 	dbNotInitializedErrorMsg string = "database not initialized"
 )
 
-var dbInstance *sql.DB
+var (
+	dbInstance  *sql.DB
+	dbInstances = map[string]*sql.DB{}
+	dbMutex     sync.RWMutex
+)
 
 func Initialize(factory func() *sql.DB) {
-	dbInstance = factory()
+	Register(DefaultInstance, factory)
+}
+
+func Register(name string, factory func() *sql.DB) {
+	instance := factory()
+
+	dbMutex.Lock()
+	dbInstances[name] = instance
+	if name == DefaultInstance {
+		dbInstance = instance
+	}
+	dbMutex.Unlock()
 
-	if err := observer.Attach(databaseObserver{dbInstance}); err != nil {
-		logging.Fatal("could not attach database to observer: %v", err)
+	if err := observer.Attach(databaseObserver{name, instance}); err != nil {
+		logging.Fatal("could not attach database %s to observer: %v", name, err)
 		return
 	}
-	logging.Info("database connected")
+	logging.Info("database %s connected", name)
+}
+
+func Instance(name string) *sql.DB {
+	if name == "" || name == DefaultInstance {
+		return dbInstance
+	}
+
+	dbMutex.RLock()
+	defer dbMutex.RUnlock()
+	return dbInstances[name]
 }
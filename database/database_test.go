@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/sdkopen/sdkopen-go/common/observer"
+)
+
+func init() {
+	observer.Initialize()
+}
+
+func TestRegister_NamedInstance(t *testing.T) {
+	db, _ := newFakeDB(t, nil)
+
+	Register("reporting", func() *sql.DB { return db })
+	t.Cleanup(func() { delete(dbInstances, "reporting") })
+
+	if Instance("reporting") != db {
+		t.Fatal("expected registered instance to be returned")
+	}
+	if Instance("unknown") != nil {
+		t.Fatal("expected nil for unknown instance")
+	}
+}
+
+func TestInitialize_SetsDefaultInstance(t *testing.T) {
+	db, _ := newFakeDB(t, nil)
+
+	Initialize(func() *sql.DB { return db })
+	t.Cleanup(func() {
+		dbInstance = nil
+		delete(dbInstances, DefaultInstance)
+	})
+
+	if dbInstance != db {
+		t.Fatal("expected default instance to be set")
+	}
+	if Instance("") != db || Instance(DefaultInstance) != db {
+		t.Fatal("expected empty and default names to resolve to the default instance")
+	}
+}
+
+func TestStatement_On_ExecutesInNamedInstance(t *testing.T) {
+	db, drv := newFakeDB(t, nil)
+
+	Register("audit", func() *sql.DB { return db })
+	t.Cleanup(func() { delete(dbInstances, "audit") })
+
+	if err := NewStatement(context.Background(), "INSERT INTO audit (event) VALUES ($1)", "login").On("audit").Execute(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{"INSERT INTO audit (event) VALUES ($1)"}
+	if events := drv.Events(); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+}
+
+func TestStatement_On_UnknownInstance(t *testing.T) {
+	err := NewStatement(context.Background(), "SELECT 1").On("missing").Execute()
+	if err == nil || err.Error() != dbNotInitializedErrorMsg {
+		t.Fatalf("expected '%s', got %v", dbNotInitializedErrorMsg, err)
+	}
+}
+
+func TestStatement_IgnoresTransactionFromOtherInstance(t *testing.T) {
+	primary, primaryDrv := newFakeDB(t, nil)
+	reporting, reportingDrv := newFakeDB(t, nil)
+
+	err := WithTransactionInInstance(context.Background(), primary, func(ctx context.Context) error {
+		return NewStatement(ctx, "INSERT INTO report (id) VALUES ($1)", 1).ExecuteInInstance(reporting)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if events := primaryDrv.Events(); !reflect.DeepEqual(events, []string{"BEGIN", "COMMIT"}) {
+		t.Fatalf("unexpected primary events: %v", events)
+	}
+	if events := reportingDrv.Events(); !reflect.DeepEqual(events, []string{"INSERT INTO report (id) VALUES ($1)"}) {
+		t.Fatalf("unexpected reporting events: %v", events)
+	}
+}
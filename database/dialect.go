@@ -0,0 +1,126 @@
+package database
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Dialect interface {
+	Name() string
+	Placeholder(position int) string
+	// QuoteIdentifier quotes each dot separated part of a table or column
+	// name, so reserved words such as order or group can be used.
+	QuoteIdentifier(name string) string
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string {
+	return "postgres"
+}
+
+func (postgresDialect) Placeholder(position int) string {
+	return "$" + strconv.Itoa(position)
+}
+
+func (postgresDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, `"`)
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string {
+	return "mysql"
+}
+
+func (mysqlDialect) Placeholder(int) string {
+	return "?"
+}
+
+func (mysqlDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, "`")
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string {
+	return "sqlite"
+}
+
+func (sqliteDialect) Placeholder(int) string {
+	return "?"
+}
+
+func (sqliteDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, `"`)
+}
+
+func quoteIdentifier(name, quote string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quote + strings.ReplaceAll(part, quote, quote+quote) + quote
+	}
+
+	return strings.Join(parts, ".")
+}
+
+var (
+	PostgresDialect Dialect = postgresDialect{}
+	MySQLDialect    Dialect = mysqlDialect{}
+	SQLiteDialect   Dialect = sqliteDialect{}
+)
+
+var (
+	dialects     = map[*sql.DB]Dialect{}
+	dialectMutex sync.RWMutex
+)
+
+// DialectOf returns the dialect of the named instance. Instances that were not
+// opened by one of the SDK connectors are assumed to be PostgreSQL.
+func DialectOf(instance string) Dialect {
+	return dialectOf(Instance(instance))
+}
+
+func dialectOf(db *sql.DB) Dialect {
+	dialectMutex.RLock()
+	defer dialectMutex.RUnlock()
+
+	if dialect, ok := dialects[db]; ok {
+		return dialect
+	}
+
+	return PostgresDialect
+}
+
+func registerDialect(db *sql.DB, dialect Dialect) {
+	dialectMutex.Lock()
+	defer dialectMutex.Unlock()
+	dialects[db] = dialect
+}
+
+func unregisterDialect(db *sql.DB) {
+	dialectMutex.Lock()
+	defer dialectMutex.Unlock()
+	delete(dialects, db)
+}
+
+// bindings collects query arguments and renders the placeholder for each one
+// in the dialect of the target instance. err keeps the first rendering error.
+type bindings struct {
+	dialect Dialect
+	args    []any
+	err     error
+}
+
+func (b *bindings) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func (b *bindings) bind(value any) string {
+	b.args = append(b.args, value)
+	return b.dialect.Placeholder(len(b.args))
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestDialect_Placeholder(t *testing.T) {
+	cases := []struct {
+		dialect  Dialect
+		name     string
+		expected string
+	}{
+		{PostgresDialect, "postgres", "$3"},
+		{MySQLDialect, "mysql", "?"},
+		{SQLiteDialect, "sqlite", "?"},
+	}
+
+	for _, c := range cases {
+		if c.dialect.Name() != c.name {
+			t.Fatalf("expected dialect %s, got %s", c.name, c.dialect.Name())
+		}
+		if placeholder := c.dialect.Placeholder(3); placeholder != c.expected {
+			t.Fatalf("expected %s placeholder %s, got %s", c.name, c.expected, placeholder)
+		}
+	}
+}
+
+func TestDialect_QuoteIdentifier(t *testing.T) {
+	cases := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{PostgresDialect, `"sales"."order"`},
+		{MySQLDialect, "`sales`.`order`"},
+		{SQLiteDialect, `"sales"."order"`},
+	}
+
+	for _, c := range cases {
+		if quoted := c.dialect.QuoteIdentifier("sales.order"); quoted != c.expected {
+			t.Fatalf("expected %s identifier %s, got %s", c.dialect.Name(), c.expected, quoted)
+		}
+	}
+
+	if quoted := PostgresDialect.QuoteIdentifier(`a"b`); quoted != `"a""b"` {
+		t.Fatalf("expected embedded quote to be doubled, got %s", quoted)
+	}
+}
+
+func TestDialectOf_Registered(t *testing.T) {
+	db, _ := newFakeDB(t, nil)
+
+	if dialectOf(db) != PostgresDialect {
+		t.Fatal("expected unregistered instance to default to postgres")
+	}
+
+	registerDialect(db, MySQLDialect)
+	if dialectOf(db) != MySQLDialect {
+		t.Fatalf("expected mysql dialect, got %s", dialectOf(db).Name())
+	}
+
+	unregisterDialect(db)
+	if dialectOf(db) != PostgresDialect {
+		t.Fatal("expected dialect to be removed")
+	}
+}
+
+func TestDialectOf_NamedInstance(t *testing.T) {
+	db, _ := newFakeDB(t, nil)
+	registerDialect(db, SQLiteDialect)
+	t.Cleanup(func() { unregisterDialect(db) })
+
+	Register("dialect-test", func() *sql.DB { return db })
+
+	if DialectOf("dialect-test") != SQLiteDialect {
+		t.Fatalf("expected sqlite dialect, got %s", DialectOf("dialect-test").Name())
+	}
+}
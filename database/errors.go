@@ -0,0 +1,195 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	commonhttp "github.com/sdkopen/sdkopen-go/common/http"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type ErrorKind int
+
+const (
+	UnknownError ErrorKind = iota
+	NotFoundError
+	UniqueViolationError
+	ForeignKeyViolationError
+	SerializationFailureError
+	DeadlockError
+)
+
+const (
+	pgUniqueViolation      pq.ErrorCode = "23505"
+	pgForeignKeyViolation  pq.ErrorCode = "23503"
+	pgSerializationFailure pq.ErrorCode = "40001"
+	pgDeadlockDetected     pq.ErrorCode = "40P01"
+
+	mysqlDuplicateEntry   uint16 = 1062
+	mysqlLockDeadlock     uint16 = 1213
+	mysqlNoReferencedRow  uint16 = 1216
+	mysqlRowIsReferenced  uint16 = 1217
+	mysqlRowIsReferenced2 uint16 = 1451
+	mysqlNoReferencedRow2 uint16 = 1452
+)
+
+var (
+	mysqlDuplicateKey  = regexp.MustCompile("for key '([^']+)'")
+	mysqlForeignKey    = regexp.MustCompile("CONSTRAINT `([^`]+)`")
+	sqliteConstraintOn = regexp.MustCompile(`UNIQUE constraint failed: ([^\s(]+(?:, [^\s(]+)*)`)
+)
+
+// Error is a classified driver error. The original error is kept and can be
+// reached with errors.As.
+type Error struct {
+	Kind       ErrorKind
+	Constraint string
+	Err        error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ClassifyError maps PostgreSQL, MySQL and SQLite driver errors to an Error.
+// Errors that are not recognised are returned with UnknownError.
+func ClassifyError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var classified *Error
+	if errors.As(err, &classified) {
+		return classified
+	}
+
+	result := &Error{Kind: UnknownError, Err: err}
+	if errors.Is(err, sql.ErrNoRows) {
+		result.Kind = NotFoundError
+		return result
+	}
+
+	var pgErr *pq.Error
+	var mysqlErr *mysql.MySQLError
+	var sqliteErr *sqlite.Error
+	switch {
+	case errors.As(err, &pgErr):
+		result.Kind, result.Constraint = classifyPostgres(pgErr)
+	case errors.As(err, &mysqlErr):
+		result.Kind, result.Constraint = classifyMySQL(mysqlErr)
+	case errors.As(err, &sqliteErr):
+		result.Kind, result.Constraint = classifySQLite(sqliteErr)
+	}
+
+	return result
+}
+
+func IsNotFound(err error) bool {
+	return errorKind(err) == NotFoundError
+}
+
+func IsUniqueViolation(err error) bool {
+	return errorKind(err) == UniqueViolationError
+}
+
+func IsForeignKeyViolation(err error) bool {
+	return errorKind(err) == ForeignKeyViolationError
+}
+
+func IsSerializationFailure(err error) bool {
+	return errorKind(err) == SerializationFailureError
+}
+
+func IsDeadlock(err error) bool {
+	return errorKind(err) == DeadlockError
+}
+
+// ConstraintName returns the violated constraint, or the columns for SQLite,
+// which does not report constraint names.
+func ConstraintName(err error) string {
+	if classified := ClassifyError(err); classified != nil {
+		return classified.Constraint
+	}
+
+	return ""
+}
+
+// ErrorStatus maps classified errors to HTTP status codes: 404 for not found
+// and 409 for constraint violations and concurrency conflicts. It matches
+// webserver.ErrorStatusMapper.
+func ErrorStatus(err error) (commonhttp.HttpStatusCode, bool) {
+	switch errorKind(err) {
+	case NotFoundError:
+		return commonhttp.StatusNotFound, true
+	case UniqueViolationError, ForeignKeyViolationError, SerializationFailureError, DeadlockError:
+		return commonhttp.StatusConflict, true
+	default:
+		return 0, false
+	}
+}
+
+func errorKind(err error) ErrorKind {
+	if classified := ClassifyError(err); classified != nil {
+		return classified.Kind
+	}
+
+	return UnknownError
+}
+
+func classifyPostgres(err *pq.Error) (ErrorKind, string) {
+	switch err.Code {
+	case pgUniqueViolation:
+		return UniqueViolationError, err.Constraint
+	case pgForeignKeyViolation:
+		return ForeignKeyViolationError, err.Constraint
+	case pgSerializationFailure:
+		return SerializationFailureError, ""
+	case pgDeadlockDetected:
+		return DeadlockError, ""
+	default:
+		return UnknownError, err.Constraint
+	}
+}
+
+func classifyMySQL(err *mysql.MySQLError) (ErrorKind, string) {
+	switch err.Number {
+	case mysqlDuplicateEntry:
+		return UniqueViolationError, submatch(mysqlDuplicateKey, err.Message)
+	case mysqlNoReferencedRow, mysqlRowIsReferenced, mysqlRowIsReferenced2, mysqlNoReferencedRow2:
+		return ForeignKeyViolationError, submatch(mysqlForeignKey, err.Message)
+	case mysqlLockDeadlock:
+		return DeadlockError, ""
+	default:
+		return UnknownError, ""
+	}
+}
+
+func classifySQLite(err *sqlite.Error) (ErrorKind, string) {
+	switch err.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return UniqueViolationError, submatch(sqliteConstraintOn, err.Error())
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return ForeignKeyViolationError, ""
+	case sqlite3.SQLITE_BUSY_SNAPSHOT:
+		return SerializationFailureError, ""
+	default:
+		return UnknownError, ""
+	}
+}
+
+func submatch(pattern *regexp.Regexp, message string) string {
+	if match := pattern.FindStringSubmatch(message); match != nil {
+		return strings.TrimSpace(match[1])
+	}
+
+	return ""
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	commonhttp "github.com/sdkopen/sdkopen-go/common/http"
+)
+
+func TestClassifyError_Postgres(t *testing.T) {
+	tests := []struct {
+		code       pq.ErrorCode
+		kind       ErrorKind
+		constraint string
+	}{
+		{"23505", UniqueViolationError, "users_email_key"},
+		{"23503", ForeignKeyViolationError, "orders_user_id_fkey"},
+		{"40001", SerializationFailureError, ""},
+		{"40P01", DeadlockError, ""},
+		{"42P01", UnknownError, ""},
+	}
+
+	for _, tt := range tests {
+		err := fmt.Errorf("insert failed: %w", &pq.Error{Code: tt.code, Constraint: tt.constraint})
+		classified := ClassifyError(err)
+		if classified.Kind != tt.kind || classified.Constraint != tt.constraint {
+			t.Errorf("code %s: expected %d/%q, got %d/%q", tt.code, tt.kind, tt.constraint, classified.Kind, classified.Constraint)
+		}
+	}
+}
+
+func TestClassifyError_MySQL(t *testing.T) {
+	duplicate := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'ana@x.com' for key 'users.email'"}
+	if !IsUniqueViolation(duplicate) || ConstraintName(duplicate) != "users.email" {
+		t.Fatalf("expected unique violation on users.email, got %+v", ClassifyError(duplicate))
+	}
+
+	foreignKey := &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`shop`.`orders`, CONSTRAINT `fk_orders_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))"}
+	if !IsForeignKeyViolation(foreignKey) || ConstraintName(foreignKey) != "fk_orders_user" {
+		t.Fatalf("expected foreign key violation on fk_orders_user, got %+v", ClassifyError(foreignKey))
+	}
+
+	if !IsDeadlock(&mysql.MySQLError{Number: 1213}) {
+		t.Fatal("expected deadlock")
+	}
+}
+
+func TestClassifyError_SQLite(t *testing.T) {
+	newRepositoryTestInstance(t, "errors-sqlite")
+	ctx := context.Background()
+
+	insert := "INSERT INTO repository_tags (code, label) VALUES ($1, $2)"
+	if err := NewStatement(ctx, insert, "go", "Go").On("errors-sqlite").Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := NewStatement(ctx, insert, "go", "Go again").On("errors-sqlite").Execute()
+
+	if !IsUniqueViolation(err) || ConstraintName(err) != "repository_tags.code" {
+		t.Fatalf("expected unique violation on repository_tags.code, got %+v", ClassifyError(err))
+	}
+
+	var classified *Error
+	if !errors.As(ClassifyError(err), &classified) || classified.Unwrap() != err {
+		t.Fatal("expected classified error to wrap the driver error")
+	}
+}
+
+func TestClassifyError_NotFound(t *testing.T) {
+	err := fmt.Errorf("user 1: %w", sql.ErrNoRows)
+
+	if !IsNotFound(err) || IsUniqueViolation(err) {
+		t.Fatal("expected not found error")
+	}
+	if ClassifyError(nil) != nil || IsNotFound(nil) {
+		t.Fatal("expected nil error not to be classified")
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		err    error
+		status commonhttp.HttpStatusCode
+		ok     bool
+	}{
+		{sql.ErrNoRows, commonhttp.StatusNotFound, true},
+		{&pq.Error{Code: "23505"}, commonhttp.StatusConflict, true},
+		{&pq.Error{Code: "23503"}, commonhttp.StatusConflict, true},
+		{&pq.Error{Code: "40001"}, commonhttp.StatusConflict, true},
+		{errors.New("boom"), 0, false},
+	}
+
+	for _, tt := range tests {
+		if status, ok := ErrorStatus(tt.err); status != tt.status || ok != tt.ok {
+			t.Errorf("%v: expected %d/%t, got %d/%t", tt.err, tt.status, tt.ok, status, ok)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+var fakeDriverCounter atomic.Int64
+
+type fakeResponse struct {
+	columns      []string
+	rows         [][]driver.Value
+	rowsAffected int64
+	lastInsertID int64
+	err          error
+}
+
+type fakeHandler func(query string, args []driver.Value) fakeResponse
+
+type fakeDriver struct {
+	mx      sync.Mutex
+	handler fakeHandler
+	events  []string
+	pingErr error
+	execCtx context.Context
+}
+
+func newFakeDB(t *testing.T, handler fakeHandler) (*sql.DB, *fakeDriver) {
+	t.Helper()
+
+	if handler == nil {
+		handler = func(string, []driver.Value) fakeResponse { return fakeResponse{} }
+	}
+
+	drv := &fakeDriver{handler: handler}
+	name := fmt.Sprintf("fakedb-%d", fakeDriverCounter.Add(1))
+	sql.Register(name, drv)
+
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("could not open fake database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db, drv
+}
+
+func (d *fakeDriver) record(event string) {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+	d.events = append(d.events, event)
+}
+
+func (d *fakeDriver) Events() []string {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+	return append([]string{}, d.events...)
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{driver: c.driver, query: query}, nil
+}
+
+func (c *fakeConn) Ping(context.Context) error {
+	c.driver.mx.Lock()
+	defer c.driver.mx.Unlock()
+	return c.driver.pingErr
+}
+
+func (d *fakeDriver) SetPingError(err error) {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+	d.pingErr = err
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.driver.record("BEGIN")
+	return &fakeTx{driver: c.driver}, nil
+}
+
+func (c *fakeConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	event := "BEGIN"
+	if isolation := sql.IsolationLevel(opts.Isolation); isolation != sql.LevelDefault {
+		event += " " + strings.ToUpper(isolation.String())
+	}
+	if opts.ReadOnly {
+		event += " READ ONLY"
+	}
+
+	c.driver.record(event)
+	return &fakeTx{driver: c.driver}, nil
+}
+
+type fakeTx struct {
+	driver *fakeDriver
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.driver.record("COMMIT")
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.driver.record("ROLLBACK")
+	return nil
+}
+
+type fakeStmt struct {
+	driver *fakeDriver
+	query  string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.driver.record(s.query)
+	response := s.driver.handler(s.query, args)
+	if response.err != nil {
+		return nil, response.err
+	}
+
+	return fakeResult{response}, nil
+}
+
+func (s *fakeStmt) ExecContext(ctx context.Context, named []driver.NamedValue) (driver.Result, error) {
+	s.driver.mx.Lock()
+	s.driver.execCtx = ctx
+	s.driver.mx.Unlock()
+
+	args := make([]driver.Value, len(named))
+	for i, arg := range named {
+		args[i] = arg.Value
+	}
+	return s.Exec(args)
+}
+
+func (d *fakeDriver) ExecContext() context.Context {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+	return d.execCtx
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.driver.record(s.query)
+	response := s.driver.handler(s.query, args)
+	if response.err != nil {
+		return nil, response.err
+	}
+
+	return &fakeRows{columns: response.columns, rows: response.rows}, nil
+}
+
+type fakeResult struct {
+	response fakeResponse
+}
+
+func (r fakeResult) LastInsertId() (int64, error) {
+	return r.response.lastInsertID, nil
+}
+
+func (r fakeResult) RowsAffected() (int64, error) {
+	return r.response.rowsAffected, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	index   int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.index >= len(r.rows) {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[r.index])
+	r.index++
+	return nil
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+)
+
+const defaultHealthTimeout time.Duration = 2 * time.Second
+
+type HealthStatus string
+
+const (
+	HealthUp   HealthStatus = "UP"
+	HealthDown HealthStatus = "DOWN"
+)
+
+type HealthReport struct {
+	Instance string        `json:"instance"`
+	Status   HealthStatus  `json:"status"`
+	Latency  time.Duration `json:"latency"`
+	Error    string        `json:"error,omitempty"`
+	Stats    sql.DBStats   `json:"stats"`
+}
+
+func (r HealthReport) Healthy() bool {
+	return r.Status == HealthUp
+}
+
+// Health pings the default instance. Without a deadline in ctx the ping is
+// bounded by a 2 second timeout.
+func Health(ctx context.Context) HealthReport {
+	return HealthOf(ctx, DefaultInstance)
+}
+
+func HealthOf(ctx context.Context, instance string) HealthReport {
+	return HealthInInstance(ctx, instance, Instance(instance))
+}
+
+func HealthInInstance(ctx context.Context, name string, instance *sql.DB) HealthReport {
+	report := HealthReport{Instance: name, Status: HealthDown}
+	if instance == nil {
+		report.Error = dbNotInitializedErrorMsg
+		return report
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultHealthTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := instance.PingContext(ctx)
+	report.Latency = time.Since(start)
+	report.Stats = instance.Stats()
+
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	report.Status = HealthUp
+	return report
+}
+
+// HealthAll reports every registered instance, ordered by name.
+func HealthAll(ctx context.Context) []HealthReport {
+	dbMutex.RLock()
+	names := make([]string, 0, len(dbInstances))
+	for name := range dbInstances {
+		names = append(names, name)
+	}
+	dbMutex.RUnlock()
+	sort.Strings(names)
+
+	reports := make([]HealthReport, len(names))
+	for i, name := range names {
+		reports[i] = HealthOf(ctx, name)
+	}
+
+	return reports
+}
+
+// HealthCheck adapts an instance to the readiness check signature used by
+// webserver.HealthController.
+func HealthCheck(instance string) func(ctx context.Context) (any, bool) {
+	return func(ctx context.Context) (any, bool) {
+		report := HealthOf(ctx, instance)
+		return report, report.Healthy()
+	}
+}
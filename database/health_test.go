@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestHealthInInstance_Up(t *testing.T) {
+	db, _ := newFakeDB(t, nil)
+
+	report := HealthInInstance(context.Background(), "primary", db)
+	if !report.Healthy() || report.Instance != "primary" || report.Error != "" {
+		t.Fatalf("expected healthy report, got %+v", report)
+	}
+	if report.Stats.MaxOpenConnections != 1 {
+		t.Fatalf("expected pool stats to be reported, got %+v", report.Stats)
+	}
+}
+
+func TestHealthInInstance_Down(t *testing.T) {
+	db, drv := newFakeDB(t, nil)
+	drv.SetPingError(errors.New("connection refused"))
+
+	report := HealthInInstance(context.Background(), "primary", db)
+	if report.Healthy() || report.Status != HealthDown || report.Error != "connection refused" {
+		t.Fatalf("expected unhealthy report, got %+v", report)
+	}
+}
+
+func TestHealthInInstance_NilInstance(t *testing.T) {
+	report := HealthInInstance(context.Background(), "missing", nil)
+	if report.Healthy() || report.Error != dbNotInitializedErrorMsg {
+		t.Fatalf("expected not initialized report, got %+v", report)
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	db, drv := newFakeDB(t, nil)
+	Register("health-check", func() *sql.DB { return db })
+	check := HealthCheck("health-check")
+
+	if detail, ready := check(context.Background()); !ready || detail.(HealthReport).Instance != "health-check" {
+		t.Fatalf("expected ready check, got %+v, %t", detail, ready)
+	}
+
+	drv.SetPingError(errors.New("down"))
+	if _, ready := check(context.Background()); ready {
+		t.Fatal("expected check not to be ready")
+	}
+
+	found := false
+	for _, report := range HealthAll(context.Background()) {
+		if report.Instance == "health-check" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected HealthAll to report registered instance")
+	}
+}
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/logging"
+)
+
+const slowQueryLogMsg string = "slow query on %s (%s, %d args): %s"
+
+type QueryOperation string
+
+const (
+	QueryExec  QueryOperation = "exec"
+	QueryQuery QueryOperation = "query"
+)
+
+// QueryEvent describes a statement going through the hook chain. Duration and
+// Err are only filled in for AfterQuery. Argument values are not exposed, as
+// they may carry personal data.
+type QueryEvent struct {
+	Instance  string
+	Operation QueryOperation
+	Query     string
+	ArgsCount int
+	Start     time.Time
+	Duration  time.Duration
+	Err       error
+}
+
+// QueryHook instruments statements. BeforeQuery may return a derived context
+// (a tracing span, for example), which is used to run the statement and is
+// handed to AfterQuery.
+type QueryHook interface {
+	BeforeQuery(ctx context.Context, event *QueryEvent) context.Context
+	AfterQuery(ctx context.Context, event *QueryEvent)
+}
+
+// QueryHookFunc is a QueryHook that only observes finished statements.
+type QueryHookFunc func(ctx context.Context, event *QueryEvent)
+
+func (f QueryHookFunc) BeforeQuery(ctx context.Context, _ *QueryEvent) context.Context {
+	return ctx
+}
+
+func (f QueryHookFunc) AfterQuery(ctx context.Context, event *QueryEvent) {
+	f(ctx, event)
+}
+
+var (
+	queryHooks     []QueryHook
+	queryHookMutex sync.RWMutex
+)
+
+// RegisterQueryHook adds a hook to the chain. Before hooks run in registration
+// order and after hooks in reverse order.
+func RegisterQueryHook(hook QueryHook) {
+	queryHookMutex.Lock()
+	defer queryHookMutex.Unlock()
+	queryHooks = append(queryHooks, hook)
+}
+
+func registeredQueryHooks() []QueryHook {
+	queryHookMutex.RLock()
+	defer queryHookMutex.RUnlock()
+	return queryHooks
+}
+
+// NewSlowQueryLogger returns a hook that logs a warning for every statement
+// slower than threshold.
+func NewSlowQueryLogger(threshold time.Duration) QueryHook {
+	return QueryHookFunc(func(_ context.Context, event *QueryEvent) {
+		if event.Duration < threshold {
+			return
+		}
+
+		instance := event.Instance
+		if instance == "" {
+			instance = DefaultInstance
+		}
+		logging.Warn(slowQueryLogMsg, instance, event.Duration, event.ArgsCount, event.Query)
+	})
+}
+
+// instrument runs fn through the hook chain with a copy of the statement bound
+// to the context returned by the before hooks.
+func (s *Statement) instrument(operation QueryOperation, fn func(s *Statement) error) error {
+	hooks := registeredQueryHooks()
+	if len(hooks) == 0 {
+		return fn(s)
+	}
+
+	event := &QueryEvent{
+		Instance:  s.instance,
+		Operation: operation,
+		Query:     s.query,
+		ArgsCount: len(s.args),
+		Start:     time.Now(),
+	}
+
+	ctx := s.ctx
+	for _, hook := range hooks {
+		ctx = hook.BeforeQuery(ctx, event)
+	}
+
+	instrumented := *s
+	instrumented.ctx = ctx
+	err := fn(&instrumented)
+
+	event.Duration = time.Since(event.Start)
+	event.Err = err
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i].AfterQuery(ctx, event)
+	}
+
+	return err
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/logging"
+)
+
+type hookContextKey string
+
+type recordingHook struct {
+	name   string
+	calls  *[]string
+	events []QueryEvent
+	ctxOk  bool
+}
+
+func (h *recordingHook) BeforeQuery(ctx context.Context, _ *QueryEvent) context.Context {
+	*h.calls = append(*h.calls, "before:"+h.name)
+	return context.WithValue(ctx, hookContextKey(h.name), true)
+}
+
+func (h *recordingHook) AfterQuery(ctx context.Context, event *QueryEvent) {
+	*h.calls = append(*h.calls, "after:"+h.name)
+	h.ctxOk = ctx.Value(hookContextKey(h.name)) == true
+	h.events = append(h.events, *event)
+}
+
+func withQueryHooks(t *testing.T, hooks ...QueryHook) {
+	t.Helper()
+
+	queryHookMutex.Lock()
+	previous := queryHooks
+	queryHooks = nil
+	queryHookMutex.Unlock()
+
+	for _, hook := range hooks {
+		RegisterQueryHook(hook)
+	}
+
+	t.Cleanup(func() {
+		queryHookMutex.Lock()
+		queryHooks = previous
+		queryHookMutex.Unlock()
+	})
+}
+
+func TestQueryHook_ChainOrder(t *testing.T) {
+	var calls []string
+	first := &recordingHook{name: "first", calls: &calls}
+	second := &recordingHook{name: "second", calls: &calls}
+	withQueryHooks(t, first, second)
+
+	db, _ := newFakeDB(t, nil)
+	if err := NewStatement(context.Background(), "UPDATE users SET name = $1 WHERE id = $2", "Ana", 1).On("users").ExecuteInInstance(db); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "before:first,before:second,after:second,after:first"
+	if got := strings.Join(calls, ","); got != expected {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if !first.ctxOk || !second.ctxOk {
+		t.Fatal("expected after hooks to receive the context returned by before hooks")
+	}
+
+	event := first.events[0]
+	if event.Operation != QueryExec || event.Instance != "users" || event.ArgsCount != 2 || event.Err != nil {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if event.Start.IsZero() || event.Duration <= 0 {
+		t.Fatalf("expected timing to be recorded, got %+v", event)
+	}
+}
+
+func TestQueryHook_QueryWithError(t *testing.T) {
+	var events []QueryEvent
+	withQueryHooks(t, QueryHookFunc(func(_ context.Context, event *QueryEvent) {
+		events = append(events, *event)
+	}))
+
+	db, _ := newFakeDB(t, func(string, []driver.Value) fakeResponse {
+		return fakeResponse{err: errors.New("boom")}
+	})
+	if _, err := QueryInInstance[int](NewStatement(context.Background(), "SELECT id FROM users"), db); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if len(events) != 1 || events[0].Operation != QueryQuery || events[0].Err == nil {
+		t.Fatalf("expected failed query event, got %+v", events)
+	}
+}
+
+func TestQueryHook_NotCalledForInvalidStatement(t *testing.T) {
+	called := false
+	withQueryHooks(t, QueryHookFunc(func(context.Context, *QueryEvent) { called = true }))
+
+	_ = NewStatement(context.Background(), "SELECT 1").ExecuteInInstance(nil)
+
+	if called {
+		t.Fatal("expected hooks not to run when the statement is not executed")
+	}
+}
+
+func TestSlowQueryLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logging.LogOutput = buf
+	logging.Initialize()
+	t.Cleanup(func() {
+		logging.LogOutput = os.Stdout
+		logging.Initialize()
+	})
+
+	hook := NewSlowQueryLogger(100 * time.Millisecond)
+	hook.AfterQuery(context.Background(), &QueryEvent{Query: "SELECT fast", Duration: 10 * time.Millisecond})
+	hook.AfterQuery(context.Background(), &QueryEvent{Query: "SELECT slow", ArgsCount: 3, Duration: 150 * time.Millisecond})
+
+	output := buf.String()
+	if strings.Contains(output, "SELECT fast") {
+		t.Fatalf("expected fast query not to be logged, got %s", output)
+	}
+	if !strings.Contains(output, "level=WARN") || !strings.Contains(output, "slow query on default (150ms, 3 args): SELECT slow") {
+		t.Fatalf("expected slow query warning, got %s", output)
+	}
+}
@@ -1,13 +1,23 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/mysql"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/sdkopen/sdkopen-go/common/env"
 	"github.com/sdkopen/sdkopen-go/logging"
 )
@@ -18,34 +28,348 @@ const (
 	migrationCouldNotConnectDBMsg  string = "Could not connect to database for migration: %v"
 	migrationExecutionWithErrorMsg string = "An error when executing database migration: %v"
 	migrationFinalizedMsg          string = "Migration finalized successfully"
+	migrationCloseErrorMsg         string = "Could not close migration %s: %v"
+	migrationDirtyErrorMsg         string = "database %s is dirty at migration version %d, fix it manually and run `force %d`"
+
+	migrationDefaultPath string = "/database/migrations"
+	migrationEmbedPrefix string = "embed://"
 )
 
-func migration(db *sql.DB) error {
-	if !env.SQL_DB_EXEC_MIGRATION {
-		logging.Info(migrationIgnoringMsg)
-		return nil
+var (
+	migrationTargets     = map[*sql.DB]migrationTarget{}
+	migrationTargetMutex sync.RWMutex
+)
+
+// migrationTarget is how a connector opens the dedicated connection its
+// migrations run on, when it needs settings the application pool must not
+// have, such as multiStatements on MySQL.
+type migrationTarget struct {
+	driver string
+	dsn    string
+}
+
+type migrationConfig struct {
+	enabled      bool
+	sourceUrl    string
+	databaseName string
+	fsys         fs.FS
+	fsDir        string
+	lockMode     MigrationLockMode
+	lockWait     time.Duration
+}
+
+type MigrationOption func(*migrationConfig)
+
+func WithMigrationPath(path string) MigrationOption {
+	return func(c *migrationConfig) {
+		c.sourceUrl = path
+	}
+}
+
+func WithMigrationFS(fsys fs.FS, dir string) MigrationOption {
+	return func(c *migrationConfig) {
+		c.fsys = fsys
+		c.fsDir = dir
+	}
+}
+
+func WithMigrationDatabaseName(name string) MigrationOption {
+	return func(c *migrationConfig) {
+		c.databaseName = name
+	}
+}
+
+type MigrationFile struct {
+	Version    uint
+	Identifier string
+}
+
+type MigrationStatus struct {
+	Version uint
+	Dirty   bool
+	Applied bool
+	Pending []MigrationFile
+}
+
+type Migrator struct {
+	migrate   *migrate.Migrate
+	source    source.Driver
+	sourceUrl string
+	lock      *migrationLock
+}
+
+func NewMigrator(db *sql.DB, opts ...MigrationOption) (*Migrator, error) {
+	cfg := migrationConfig{
+		sourceUrl:    env.SQL_DB_MIGRATION_SOURCE_URL,
+		databaseName: env.SQL_DB_NAME,
+		lockMode:     MigrationLockMode(env.SQL_DB_MIGRATION_LOCK_MODE),
+		lockWait:     env.SQL_DB_MIGRATION_LOCK_WAIT,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return newMigrator(db, cfg)
+}
+
+func newMigrator(db *sql.DB, cfg migrationConfig) (*Migrator, error) {
+	if db == nil {
+		return nil, errors.New(dbNotInitializedErrorMsg)
+	}
+
+	sourceName, sourceUrl, src, err := openMigrationSource(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, conn, err := openMigrationDriver(db, cfg.databaseName)
+	if err != nil {
+		closeMigrationPart("source", src.Close)
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance(sourceName, src, cfg.databaseName, driver)
+	if err != nil {
+		closeMigrationPart("source", src.Close)
+		closeMigrationPart("database driver", driver.Close)
+		return nil, err
+	}
+
+	migrator := &Migrator{migrate: m, source: src, sourceUrl: sourceUrl}
+	if dialectOf(db) == PostgresDialect {
+		migrator.lock = newMigrationLock(conn, cfg)
+	}
+
+	return migrator, nil
+}
+
+// openMigrationDriver builds the golang-migrate driver for the dialect of db.
+// PostgreSQL and MySQL run on a dedicated connection and SQLite shares db, so
+// closing the driver never closes the application pool. The connection comes
+// from the migration target of db when its connector registered one.
+func openMigrationDriver(db *sql.DB, databaseName string) (database.Driver, *sql.Conn, error) {
+	if dialectOf(db) == SQLiteDialect {
+		driver, err := sqlite.WithInstance(db, &sqlite.Config{DatabaseName: databaseName})
+		if err != nil {
+			return nil, nil, err
+		}
+		return sharedMigrationDriver{driver}, nil, nil
+	}
+
+	pool := db
+	target, dedicated := migrationTargetOf(db)
+	if dedicated {
+		var err error
+		if pool, err = sql.Open(target.driver, target.dsn); err != nil {
+			return nil, nil, err
+		}
+		pool.SetMaxOpenConns(1)
+	}
+
+	ctx := context.Background()
+	conn, err := pool.Conn(ctx)
+	if err != nil {
+		if dedicated {
+			closeMigrationPart("database", pool.Close)
+		}
+		return nil, nil, err
+	}
+
+	var driver database.Driver
+	if dialectOf(db) == MySQLDialect {
+		driver, err = mysql.WithConnection(ctx, conn, &mysql.Config{DatabaseName: databaseName})
+	} else {
+		driver, err = postgres.WithConnection(ctx, conn, &postgres.Config{DatabaseName: databaseName})
+	}
+	if err != nil {
+		closeMigrationPart("connection", conn.Close)
+		if dedicated {
+			closeMigrationPart("database", pool.Close)
+		}
+		return nil, nil, err
+	}
+
+	if dedicated {
+		driver = dedicatedMigrationDriver{Driver: driver, pool: pool}
+	}
+	return driver, conn, nil
+}
+
+type sharedMigrationDriver struct {
+	database.Driver
+}
+
+func (sharedMigrationDriver) Close() error {
+	return nil
+}
+
+// dedicatedMigrationDriver also closes the pool opened for the migration
+// target.
+type dedicatedMigrationDriver struct {
+	database.Driver
+	pool *sql.DB
+}
+
+func (d dedicatedMigrationDriver) Close() error {
+	return errors.Join(d.Driver.Close(), d.pool.Close())
+}
+
+func registerMigrationTarget(db *sql.DB, target migrationTarget) {
+	migrationTargetMutex.Lock()
+	defer migrationTargetMutex.Unlock()
+	migrationTargets[db] = target
+}
+
+func unregisterMigrationTarget(db *sql.DB) {
+	migrationTargetMutex.Lock()
+	defer migrationTargetMutex.Unlock()
+	delete(migrationTargets, db)
+}
+
+func migrationTargetOf(db *sql.DB) (migrationTarget, bool) {
+	migrationTargetMutex.RLock()
+	defer migrationTargetMutex.RUnlock()
+
+	target, ok := migrationTargets[db]
+	return target, ok
+}
+
+func openMigrationSource(cfg migrationConfig) (string, string, source.Driver, error) {
+	if cfg.fsys != nil {
+		dir := cfg.fsDir
+		if dir == "" {
+			dir = "."
+		}
+		src, err := iofs.New(cfg.fsys, dir)
+		return "iofs", migrationEmbedPrefix + dir, src, err
 	}
 
-	sourceUrl := env.SQL_DB_MIGRATION_SOURCE_URL
+	sourceUrl := cfg.sourceUrl
 	if sourceUrl == "" {
 		pwd, _ := os.Getwd()
-		sourceUrl = pwd + "/database/migrations"
+		sourceUrl = pwd + migrationDefaultPath
+	}
+
+	src, err := source.Open("file://" + sourceUrl)
+	return "file", sourceUrl, src, err
+}
+
+func (m *Migrator) Up() error {
+	return ignoreNoChange(m.migrate.Up())
+}
+
+func (m *Migrator) Down() error {
+	return ignoreNoChange(m.migrate.Down())
+}
+
+func (m *Migrator) Steps(n int) error {
+	return ignoreNoChange(m.migrate.Steps(n))
+}
+
+func (m *Migrator) Migrate(version uint) error {
+	return ignoreNoChange(m.migrate.Migrate(version))
+}
+
+func (m *Migrator) Force(version int) error {
+	return m.migrate.Force(version)
+}
+
+func (m *Migrator) Status() (MigrationStatus, error) {
+	status := MigrationStatus{}
+
+	version, dirty, err := m.migrate.Version()
+	switch {
+	case errors.Is(err, migrate.ErrNilVersion):
+	case err != nil:
+		return status, err
+	default:
+		status.Version, status.Dirty, status.Applied = version, dirty, true
 	}
 
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	pending, err := m.pending(status)
+	if err != nil {
+		return status, err
+	}
+	status.Pending = pending
+
+	return status, nil
+}
+
+func (m *Migrator) Close() error {
+	sourceErr, databaseErr := m.migrate.Close()
+	return errors.Join(sourceErr, databaseErr)
+}
+
+func (m *Migrator) pending(status MigrationStatus) ([]MigrationFile, error) {
+	pending := make([]MigrationFile, 0)
+
+	version, err := m.source.First()
+	for err == nil {
+		if !status.Applied || version > status.Version {
+			file, ok, readErr := m.migrationFile(version)
+			if readErr != nil {
+				return nil, readErr
+			}
+			if ok {
+				pending = append(pending, file)
+			}
+		}
+		version, err = m.source.Next(version)
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return pending, nil
+}
+
+func (m *Migrator) migrationFile(version uint) (MigrationFile, bool, error) {
+	reader, identifier, err := m.source.ReadUp(version)
+	if errors.Is(err, os.ErrNotExist) {
+		return MigrationFile{}, false, nil
+	}
+	if err != nil {
+		return MigrationFile{}, false, err
+	}
+	closeMigrationPart("file", reader.Close)
+
+	return MigrationFile{Version: version, Identifier: identifier}, true, nil
+}
+
+func migration(db *sql.DB, cfg migrationConfig) error {
+	if !cfg.enabled {
+		logging.Info(migrationIgnoringMsg)
+		return nil
+	}
+
+	migrator, err := newMigrator(db, cfg)
 	if err != nil {
 		logging.Error(migrationCouldNotConnectDBMsg, err)
 		return err
 	}
+	defer closeMigrationPart("migrator", migrator.Close)
+
+	if migrator.lock != nil {
+		acquired, err := migrator.lock.acquire(context.Background())
+		if err != nil || !acquired {
+			return err
+		}
+		defer migrator.lock.release()
+	}
 
-	logging.Info(migrationStartingMsg, sourceUrl)
-	migrationDBInstance, err := migrate.NewWithDatabaseInstance("file://"+sourceUrl, env.SQL_DB_NAME, driver)
+	status, err := migrator.Status()
 	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		err = fmt.Errorf(migrationDirtyErrorMsg, cfg.databaseName, status.Version, status.Version)
 		logging.Error(migrationExecutionWithErrorMsg, err)
 		return err
 	}
 
-	if err = migrationDBInstance.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+	logging.Info(migrationStartingMsg, migrator.sourceUrl)
+	if err = migrator.Up(); err != nil {
 		logging.Error(migrationExecutionWithErrorMsg, err)
 		return err
 	}
@@ -53,3 +377,17 @@ func migration(db *sql.DB) error {
 	logging.Info(migrationFinalizedMsg)
 	return nil
 }
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+
+	return err
+}
+
+func closeMigrationPart(part string, close func() error) {
+	if err := close(); err != nil {
+		logging.Error(migrationCloseErrorMsg, part, err)
+	}
+}
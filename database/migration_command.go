@@ -0,0 +1,84 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	migrationCommandUsage string = `usage: <command> [argument]
+
+commands:
+  up              apply all pending migrations
+  down            revert all applied migrations
+  steps <n>       apply (n > 0) or revert (n < 0) n migrations
+  goto <version>  migrate up or down to the given version
+  force <version> set the version without running migrations (fixes dirty state)
+  status          print the current version and pending migrations`
+
+	migrationUnknownCommandMsg string = "unknown migration command %q"
+	migrationArgumentErrorMsg  string = "command %s expects an integer argument: %w"
+)
+
+func RunMigrationCommand(m *Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(migrationCommandUsage)
+	}
+
+	command := args[0]
+	switch command {
+	case "up":
+		return m.Up()
+	case "down":
+		return m.Down()
+	case "status":
+		return printMigrationStatus(m, out)
+	case "steps", "goto", "force":
+		if len(args) < 2 {
+			return fmt.Errorf(migrationArgumentErrorMsg, command, errors.New("missing argument"))
+		}
+		value, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf(migrationArgumentErrorMsg, command, err)
+		}
+		return runMigrationVersionCommand(m, command, value)
+	default:
+		return fmt.Errorf(migrationUnknownCommandMsg+"\n\n%s", command, migrationCommandUsage)
+	}
+}
+
+func runMigrationVersionCommand(m *Migrator, command string, value int) error {
+	switch command {
+	case "steps":
+		return m.Steps(value)
+	case "goto":
+		if value < 0 {
+			return fmt.Errorf(migrationArgumentErrorMsg, command, errors.New("version must not be negative"))
+		}
+		return m.Migrate(uint(value))
+	default:
+		return m.Force(value)
+	}
+}
+
+func printMigrationStatus(m *Migrator, out io.Writer) error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	if status.Applied {
+		fmt.Fprintf(out, "version: %d (dirty: %t)\n", status.Version, status.Dirty)
+	} else {
+		fmt.Fprintln(out, "version: none")
+	}
+
+	fmt.Fprintf(out, "pending: %d\n", len(status.Pending))
+	for _, file := range status.Pending {
+		fmt.Fprintf(out, "  %d %s\n", file.Version, file.Identifier)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunMigrationCommand_NoArgs(t *testing.T) {
+	err := RunMigrationCommand(nil, nil, &bytes.Buffer{})
+	if err == nil || !strings.HasPrefix(err.Error(), "usage:") {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
+
+func TestRunMigrationCommand_UnknownCommand(t *testing.T) {
+	err := RunMigrationCommand(nil, []string{"drop"}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), `unknown migration command "drop"`) {
+		t.Fatalf("expected unknown command error, got %v", err)
+	}
+}
+
+func TestRunMigrationCommand_MissingArgument(t *testing.T) {
+	for _, command := range []string{"steps", "goto", "force"} {
+		if err := RunMigrationCommand(nil, []string{command}, &bytes.Buffer{}); err == nil {
+			t.Fatalf("expected error for %s without argument, got nil", command)
+		}
+	}
+}
+
+func TestRunMigrationCommand_InvalidArgument(t *testing.T) {
+	if err := RunMigrationCommand(nil, []string{"steps", "two"}, &bytes.Buffer{}); err == nil {
+		t.Fatal("expected error for non integer argument, got nil")
+	}
+	if err := RunMigrationCommand(nil, []string{"goto", "-1"}, &bytes.Buffer{}); err == nil {
+		t.Fatal("expected error for negative version, got nil")
+	}
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/logging"
+)
+
+type MigrationLockMode string
+
+const (
+	MigrationLockWait MigrationLockMode = "wait"
+	MigrationLockSkip MigrationLockMode = "skip"
+
+	migrationLockKeyPrefix string = "sdkopen_migration:"
+
+	migrationLockBusyMsg        string = "Migration lock for database %s is held by pid %d (application: %s, client: %s, since: %s)"
+	migrationLockUnknownMsg     string = "Migration lock for database %s is held by another session"
+	migrationLockSkippedMsg     string = "Skipping migration because another instance holds the lock for database %s"
+	migrationLockAcquiredMsg    string = "Migration lock acquired for database %s"
+	migrationLockReleaseMsg     string = "Could not release migration lock for database %s: %v"
+	migrationLockTimeoutMsg     string = "timed out after %s waiting for migration lock on database %s"
+	migrationLockInvalidModeMsg string = "invalid migration lock mode %q, expected wait or skip"
+
+	migrationTryLockQuery string = "SELECT pg_try_advisory_lock($1)"
+	migrationUnlockQuery  string = "SELECT pg_advisory_unlock($1)"
+	migrationHolderQuery  string = `SELECT a.pid, COALESCE(a.application_name, ''), COALESCE(HOST(a.client_addr), ''), a.backend_start
+FROM pg_locks l JOIN pg_stat_activity a ON a.pid = l.pid
+WHERE l.locktype = 'advisory' AND l.granted AND l.objsubid = 1
+  AND l.classid = (($1::bigint >> 32) & 4294967295) AND l.objid = ($1::bigint & 4294967295)
+LIMIT 1`
+)
+
+var migrationLockPollInterval = 500 * time.Millisecond
+
+type migrationLock struct {
+	conn         *sql.Conn
+	key          int64
+	databaseName string
+	mode         MigrationLockMode
+	wait         time.Duration
+}
+
+func WithMigrationLock(mode MigrationLockMode, wait time.Duration) MigrationOption {
+	return func(c *migrationConfig) {
+		c.lockMode = mode
+		c.lockWait = wait
+	}
+}
+
+func newMigrationLock(conn *sql.Conn, cfg migrationConfig) *migrationLock {
+	mode := cfg.lockMode
+	if mode == "" {
+		mode = MigrationLockWait
+	}
+
+	return &migrationLock{
+		conn:         conn,
+		key:          migrationLockKey(cfg.databaseName),
+		databaseName: cfg.databaseName,
+		mode:         mode,
+		wait:         cfg.lockWait,
+	}
+}
+
+func migrationLockKey(databaseName string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(migrationLockKeyPrefix + databaseName))
+	return int64(hash.Sum64())
+}
+
+// acquire returns false without error when the lock is busy in skip mode.
+// In wait mode it polls until the lock is free or the wait elapses; a
+// non-positive wait means no limit.
+func (l *migrationLock) acquire(ctx context.Context) (bool, error) {
+	if l.mode != MigrationLockWait && l.mode != MigrationLockSkip {
+		return false, fmt.Errorf(migrationLockInvalidModeMsg, l.mode)
+	}
+
+	if l.wait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.wait)
+		defer cancel()
+	}
+
+	for attempt := 0; ; attempt++ {
+		acquired, err := l.try(ctx)
+		if err != nil {
+			return false, l.timeoutError(ctx, err)
+		}
+		if acquired {
+			logging.Info(migrationLockAcquiredMsg, l.databaseName)
+			return true, nil
+		}
+
+		if attempt == 0 {
+			l.logHolder(ctx)
+		}
+		if l.mode == MigrationLockSkip {
+			logging.Info(migrationLockSkippedMsg, l.databaseName)
+			return false, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, l.timeoutError(ctx, ctx.Err())
+		case <-time.After(migrationLockPollInterval):
+		}
+	}
+}
+
+func (l *migrationLock) release() {
+	var released bool
+	if err := l.conn.QueryRowContext(context.Background(), migrationUnlockQuery, l.key).Scan(&released); err != nil {
+		logging.Error(migrationLockReleaseMsg, l.databaseName, err)
+	}
+}
+
+func (l *migrationLock) try(ctx context.Context) (bool, error) {
+	var acquired bool
+	err := l.conn.QueryRowContext(ctx, migrationTryLockQuery, l.key).Scan(&acquired)
+	return acquired, err
+}
+
+func (l *migrationLock) logHolder(ctx context.Context) {
+	var (
+		pid          int64
+		application  string
+		client       string
+		backendStart time.Time
+	)
+
+	err := l.conn.QueryRowContext(ctx, migrationHolderQuery, l.key).Scan(&pid, &application, &client, &backendStart)
+	if err != nil {
+		logging.Warn(migrationLockUnknownMsg, l.databaseName)
+		return
+	}
+
+	logging.Warn(migrationLockBusyMsg, l.databaseName, pid, application, client, backendStart.Format(time.RFC3339))
+}
+
+func (l *migrationLock) timeoutError(ctx context.Context, err error) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf(migrationLockTimeoutMsg, l.wait, l.databaseName)
+	}
+
+	return err
+}
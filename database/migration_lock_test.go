@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestMigrationLock(t *testing.T, mode MigrationLockMode, wait time.Duration, handler fakeHandler) (*migrationLock, *fakeDriver) {
+	t.Helper()
+
+	db, drv := newFakeDB(t, handler)
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("could not open connection: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return newMigrationLock(conn, migrationConfig{databaseName: "orders", lockMode: mode, lockWait: wait}), drv
+}
+
+func lockHandler(busyAttempts int64) fakeHandler {
+	var attempts atomic.Int64
+	return func(query string, _ []driver.Value) fakeResponse {
+		switch query {
+		case migrationTryLockQuery:
+			acquired := attempts.Add(1) > busyAttempts
+			return fakeResponse{columns: []string{"pg_try_advisory_lock"}, rows: [][]driver.Value{{acquired}}}
+		case migrationUnlockQuery:
+			return fakeResponse{columns: []string{"pg_advisory_unlock"}, rows: [][]driver.Value{{true}}}
+		case migrationHolderQuery:
+			return fakeResponse{
+				columns: []string{"pid", "application_name", "client_addr", "backend_start"},
+				rows:    [][]driver.Value{{int64(42), "orders-api", "10.0.0.7", time.Now()}},
+			}
+		}
+		return fakeResponse{}
+	}
+}
+
+func countQuery(events []string, query string) int {
+	count := 0
+	for _, event := range events {
+		if event == query {
+			count++
+		}
+	}
+	return count
+}
+
+func TestMigrationLockKey(t *testing.T) {
+	if migrationLockKey("orders") != migrationLockKey("orders") {
+		t.Fatal("expected the same key for the same database")
+	}
+	if migrationLockKey("orders") == migrationLockKey("billing") {
+		t.Fatal("expected different keys for different databases")
+	}
+}
+
+func TestMigrationLock_Acquired(t *testing.T) {
+	lock, drv := newTestMigrationLock(t, MigrationLockWait, time.Second, lockHandler(0))
+
+	acquired, err := lock.acquire(context.Background())
+	if err != nil || !acquired {
+		t.Fatalf("expected lock acquired, got %t, %v", acquired, err)
+	}
+	lock.release()
+
+	events := drv.Events()
+	if countQuery(events, migrationHolderQuery) != 0 {
+		t.Fatal("expected holder not to be queried when lock is free")
+	}
+	if countQuery(events, migrationUnlockQuery) != 1 {
+		t.Fatalf("expected lock to be released, got events %v", events)
+	}
+}
+
+func TestMigrationLock_SkipWhenBusy(t *testing.T) {
+	lock, drv := newTestMigrationLock(t, MigrationLockSkip, time.Second, lockHandler(1))
+
+	acquired, err := lock.acquire(context.Background())
+	if err != nil || acquired {
+		t.Fatalf("expected lock skipped without error, got %t, %v", acquired, err)
+	}
+
+	events := drv.Events()
+	if countQuery(events, migrationTryLockQuery) != 1 {
+		t.Fatalf("expected a single lock attempt, got events %v", events)
+	}
+	if countQuery(events, migrationHolderQuery) != 1 {
+		t.Fatalf("expected lock holder to be logged, got events %v", events)
+	}
+}
+
+func TestMigrationLock_WaitUntilReleased(t *testing.T) {
+	migrationLockPollInterval = time.Millisecond
+	t.Cleanup(func() { migrationLockPollInterval = 500 * time.Millisecond })
+
+	lock, drv := newTestMigrationLock(t, MigrationLockWait, time.Second, lockHandler(3))
+
+	acquired, err := lock.acquire(context.Background())
+	if err != nil || !acquired {
+		t.Fatalf("expected lock acquired after waiting, got %t, %v", acquired, err)
+	}
+
+	events := drv.Events()
+	if countQuery(events, migrationTryLockQuery) != 4 {
+		t.Fatalf("expected 4 lock attempts, got events %v", events)
+	}
+	if countQuery(events, migrationHolderQuery) != 1 {
+		t.Fatalf("expected lock holder to be logged once, got events %v", events)
+	}
+}
+
+func TestMigrationLock_WaitTimeout(t *testing.T) {
+	migrationLockPollInterval = time.Millisecond
+	t.Cleanup(func() { migrationLockPollInterval = 500 * time.Millisecond })
+
+	lock, _ := newTestMigrationLock(t, MigrationLockWait, 20*time.Millisecond, lockHandler(1<<30))
+
+	acquired, err := lock.acquire(context.Background())
+	if acquired || err == nil {
+		t.Fatalf("expected timeout error, got %t, %v", acquired, err)
+	}
+	if !strings.Contains(err.Error(), "timed out") || !strings.Contains(err.Error(), "orders") {
+		t.Fatalf("expected timeout error mentioning database, got %v", err)
+	}
+}
+
+func TestMigrationLock_InvalidMode(t *testing.T) {
+	lock, drv := newTestMigrationLock(t, "fail", time.Second, lockHandler(0))
+
+	if _, err := lock.acquire(context.Background()); err == nil {
+		t.Fatal("expected error for invalid lock mode, got nil")
+	}
+	if len(drv.Events()) != 0 {
+		t.Fatalf("expected no queries for invalid mode, got %v", drv.Events())
+	}
+}
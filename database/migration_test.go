@@ -0,0 +1,169 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+func newTestMigrationSource(t *testing.T, files ...string) source.Driver {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte("SELECT 1;"), 0o644); err != nil {
+			t.Fatalf("could not write migration file: %v", err)
+		}
+	}
+
+	src, err := source.Open("file://" + dir)
+	if err != nil {
+		t.Fatalf("could not open migration source: %v", err)
+	}
+	t.Cleanup(func() { _ = src.Close() })
+
+	return src
+}
+
+func TestMigration_Disabled(t *testing.T) {
+	if err := migration(nil, migrationConfig{enabled: false}); err != nil {
+		t.Fatalf("expected no error when migration is disabled, got %v", err)
+	}
+}
+
+func TestNewMigrator_NilInstance(t *testing.T) {
+	_, err := NewMigrator(nil, WithMigrationPath(t.TempDir()))
+	if err == nil || err.Error() != dbNotInitializedErrorMsg {
+		t.Fatalf("expected '%s', got %v", dbNotInitializedErrorMsg, err)
+	}
+}
+
+func TestMigrator_Pending_NothingApplied(t *testing.T) {
+	src := newTestMigrationSource(t,
+		"1_create_users.up.sql", "1_create_users.down.sql",
+		"2_create_orders.up.sql", "2_create_orders.down.sql",
+	)
+	migrator := &Migrator{source: src}
+
+	pending, err := migrator.pending(MigrationStatus{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("expected 2 pending migrations, got %d", len(pending))
+	}
+	if pending[0].Version != 1 || pending[0].Identifier != "create_users" {
+		t.Fatalf("unexpected first pending migration: %+v", pending[0])
+	}
+}
+
+func TestMigrator_Pending_AfterCurrentVersion(t *testing.T) {
+	src := newTestMigrationSource(t,
+		"1_create_users.up.sql",
+		"2_create_orders.up.sql",
+		"3_add_index.up.sql",
+		"4_only_down.down.sql",
+	)
+	migrator := &Migrator{source: src}
+
+	pending, err := migrator.pending(MigrationStatus{Version: 2, Applied: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(pending) != 1 || pending[0].Version != 3 {
+		t.Fatalf("expected only version 3 pending, got %+v", pending)
+	}
+}
+
+func TestMigrator_Pending_EmptySource(t *testing.T) {
+	migrator := &Migrator{source: newTestMigrationSource(t)}
+
+	pending, err := migrator.pending(MigrationStatus{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no pending migrations, got %d", len(pending))
+	}
+}
+
+func TestOpenMigrationSource_FS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/1_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INT);")},
+		"migrations/1_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"migrations/2_add_email.up.sql":      {Data: []byte("ALTER TABLE users ADD email TEXT;")},
+	}
+
+	name, url, src, err := openMigrationSource(migrationConfig{fsys: fsys, fsDir: "migrations", sourceUrl: "/ignored"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer src.Close()
+
+	if name != "iofs" {
+		t.Fatalf("expected source name iofs, got %s", name)
+	}
+	if url != "embed://migrations" {
+		t.Fatalf("expected url embed://migrations, got %s", url)
+	}
+
+	pending, err := (&Migrator{source: src}).pending(MigrationStatus{Version: 1, Applied: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(pending) != 1 || pending[0].Version != 2 || pending[0].Identifier != "add_email" {
+		t.Fatalf("unexpected pending migrations: %+v", pending)
+	}
+}
+
+func TestOpenMigrationSource_FSMissingDir(t *testing.T) {
+	_, _, _, err := openMigrationSource(migrationConfig{fsys: fstest.MapFS{}, fsDir: "missing"})
+	if err == nil {
+		t.Fatal("expected error for missing directory, got nil")
+	}
+}
+
+func TestOpenMigrationSource_Path(t *testing.T) {
+	dir := t.TempDir()
+
+	name, url, src, err := openMigrationSource(migrationConfig{sourceUrl: dir})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer src.Close()
+
+	if name != "file" || url != dir {
+		t.Fatalf("expected file source at %s, got %s at %s", dir, name, url)
+	}
+}
+
+func TestOpenMigrationDriver_UsesMigrationTarget(t *testing.T) {
+	db, appDrv := newFakeDB(t, nil)
+
+	targetDrv := &fakeDriver{handler: func(string, []driver.Value) fakeResponse {
+		return fakeResponse{columns: []string{"value"}, rows: [][]driver.Value{{"public"}}}
+	}}
+	name := fmt.Sprintf("fakedb-%d", fakeDriverCounter.Add(1))
+	sql.Register(name, targetDrv)
+	registerMigrationTarget(db, migrationTarget{driver: name})
+	t.Cleanup(func() { unregisterMigrationTarget(db) })
+
+	migrationDriver, conn, err := openMigrationDriver(db, "shop")
+	if err == nil {
+		_ = migrationDriver.Close()
+		_ = conn.Close()
+	}
+
+	if len(targetDrv.Events()) == 0 {
+		t.Fatal("expected the migration to run on the migration target")
+	}
+	if events := appDrv.Events(); len(events) != 0 {
+		t.Fatalf("expected no migration queries on the application pool, got %v", events)
+	}
+}
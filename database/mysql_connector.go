@@ -0,0 +1,137 @@
+package database
+
+import (
+	"database/sql"
+	"net"
+	"strconv"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/sdkopen/sdkopen-go/common/env"
+	"github.com/sdkopen/sdkopen-go/logging"
+)
+
+const (
+	mysqlDriver      string = "mysql"
+	mysqlDefaultPort int    = 3306
+)
+
+type MySQLConnector struct {
+	host     string
+	port     int
+	database string
+	username string
+	password string
+	sslMode  string
+	connectorConfig
+}
+
+func NewDefaultMySQLConnector(opts ...ConnectorOption) *MySQLConnector {
+	return newMySQLConnector(env.DefaultSqlDB(), opts)
+}
+
+func NewPrefixedMySQLConnector(prefix string, opts ...ConnectorOption) *MySQLConnector {
+	cfg, err := env.LoadSqlDB(prefix)
+	if err != nil {
+		logging.Fatal("%s", err.Error())
+	}
+
+	return newMySQLConnector(cfg, opts)
+}
+
+// newMySQLConnector uses the MySQL port when SQL_DB_PORT is not set, as the
+// variable defaults to the PostgreSQL one.
+func newMySQLConnector(cfg env.SqlDB, opts []ConnectorOption) *MySQLConnector {
+	port := cfg.Port
+	if !cfg.PortSet {
+		port = mysqlDefaultPort
+	}
+
+	return &MySQLConnector{
+		host:            cfg.Url,
+		port:            port,
+		database:        cfg.Name,
+		username:        cfg.Username,
+		password:        cfg.Password,
+		sslMode:         cfg.SslMode,
+		connectorConfig: newConnectorConfig(cfg, opts),
+	}
+}
+
+func (c *MySQLConnector) Connect() *sql.DB {
+	return c.open(connectTarget{
+		driver:       mysqlDriver,
+		dialect:      MySQLDialect,
+		dsn:          c.getDSN(c.host, c.port),
+		address:      net.JoinHostPort(c.host, strconv.Itoa(c.port)),
+		replicaDSN:   c.getReplicaDSN,
+		migrationDSN: c.getMigrationDSN(),
+	})
+}
+
+// getDSN enables parseTime so DATETIME columns scan into time.Time and
+// clientFoundRows so RowsAffected counts matched rows like the other drivers.
+func (c *MySQLConnector) getDSN(host string, port int) string {
+	return c.config(host, port).FormatDSN()
+}
+
+// getMigrationDSN also enables multiStatements, as migration files usually
+// hold more than one statement. It is only used by the migration connection,
+// so the application pool never runs stacked queries.
+func (c *MySQLConnector) getMigrationDSN() string {
+	cfg := c.config(c.host, c.port)
+	cfg.MultiStatements = true
+
+	return cfg.FormatDSN()
+}
+
+func (c *MySQLConnector) config(host string, port int) *mysql.Config {
+	if port == 0 {
+		port = mysqlDefaultPort
+	}
+
+	cfg := mysql.NewConfig()
+	cfg.User = c.username
+	cfg.Passwd = c.password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(host, strconv.Itoa(port))
+	cfg.DBName = c.database
+	cfg.TLSConfig = mysqlTLSConfig(c.sslMode)
+	cfg.ParseTime = true
+	cfg.ClientFoundRows = true
+
+	return cfg
+}
+
+func (c *MySQLConnector) getReplicaDSN(address string) (string, error) {
+	host, port, err := splitHostPort(address, c.port)
+	if err != nil {
+		return "", err
+	}
+
+	return c.getDSN(host, port), nil
+}
+
+// mysqlTLSConfig maps the PostgreSQL style SQL_DB_SSL_MODE values to the
+// go-sql-driver tls parameter; any other value is passed through unchanged.
+func mysqlTLSConfig(sslMode string) string {
+	switch sslMode {
+	case "", "disable":
+		return ""
+	case "require":
+		return "skip-verify"
+	case "verify-ca", "verify-full":
+		return "true"
+	default:
+		return sslMode
+	}
+}
+
+func MySQL() *sql.DB {
+	return NewDefaultMySQLConnector().Connect()
+}
+
+func MySQLWithPrefix(prefix string) func() *sql.DB {
+	return func() *sql.DB {
+		return NewPrefixedMySQLConnector(prefix).Connect()
+	}
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/sdkopen/sdkopen-go/common/env"
+)
+
+func TestNewMySQLConnector(t *testing.T) {
+	connector := newMySQLConnector(env.SqlDB{
+		Url:          "mysql.local",
+		Port:         3307,
+		PortSet:      true,
+		Name:         "shop",
+		Username:     "app",
+		Password:     "secret",
+		MaxOpenConns: 10,
+	}, []ConnectorOption{WithMaxOpenConns(20)})
+
+	if connector.host != "mysql.local" || connector.port != 3307 || connector.database != "shop" {
+		t.Fatalf("unexpected connector: %+v", connector)
+	}
+	if connector.pool.MaxOpenConns != 20 {
+		t.Fatalf("expected MaxOpenConns=20, got %d", connector.pool.MaxOpenConns)
+	}
+}
+
+func TestNewMySQLConnector_DefaultPort(t *testing.T) {
+	connector := newMySQLConnector(env.SqlDB{Url: "mysql.local", Port: 5432}, nil)
+
+	if connector.port != mysqlDefaultPort {
+		t.Fatalf("expected port %d when SQL_DB_PORT is not set, got %d", mysqlDefaultPort, connector.port)
+	}
+}
+
+func TestMySQLConnector_GetDSN(t *testing.T) {
+	connector := &MySQLConnector{
+		username: "app",
+		password: "secret",
+		database: "shop",
+		sslMode:  "require",
+	}
+
+	cfg, err := mysql.ParseDSN(connector.getDSN("mysql.local", 3307))
+	if err != nil {
+		t.Fatalf("expected valid dsn, got %v", err)
+	}
+	if cfg.User != "app" || cfg.Passwd != "secret" || cfg.DBName != "shop" {
+		t.Fatalf("unexpected credentials: %+v", cfg)
+	}
+	if cfg.Addr != "mysql.local:3307" {
+		t.Fatalf("expected addr mysql.local:3307, got %s", cfg.Addr)
+	}
+	if cfg.MultiStatements || !cfg.ParseTime {
+		t.Fatal("expected parseTime enabled and multiStatements disabled on the application dsn")
+	}
+	if cfg.TLSConfig != "skip-verify" {
+		t.Fatalf("expected tls=skip-verify, got %s", cfg.TLSConfig)
+	}
+}
+
+func TestMySQLConnector_GetMigrationDSN(t *testing.T) {
+	connector := &MySQLConnector{host: "mysql.local", port: 3307, username: "app", database: "shop"}
+
+	cfg, err := mysql.ParseDSN(connector.getMigrationDSN())
+	if err != nil {
+		t.Fatalf("expected valid dsn, got %v", err)
+	}
+	if !cfg.MultiStatements || !cfg.ParseTime || cfg.Addr != "mysql.local:3307" || cfg.DBName != "shop" {
+		t.Fatalf("expected multiStatements on the migration dsn, got %+v", cfg)
+	}
+}
+
+func TestMySQLConnector_GetDSN_DefaultPort(t *testing.T) {
+	connector := &MySQLConnector{database: "shop"}
+
+	cfg, err := mysql.ParseDSN(connector.getDSN("localhost", 0))
+	if err != nil {
+		t.Fatalf("expected valid dsn, got %v", err)
+	}
+	if cfg.Addr != "localhost:3306" {
+		t.Fatalf("expected addr localhost:3306, got %s", cfg.Addr)
+	}
+}
+
+func TestMySQLConnector_GetReplicaDSN(t *testing.T) {
+	connector := &MySQLConnector{port: 3306, database: "shop"}
+
+	dsn, err := connector.getReplicaDSN("replica-1:3310")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	cfg, _ := mysql.ParseDSN(dsn)
+	if cfg.Addr != "replica-1:3310" {
+		t.Fatalf("expected addr replica-1:3310, got %s", cfg.Addr)
+	}
+
+	if _, err = connector.getReplicaDSN("replica-2:abc"); err == nil {
+		t.Fatal("expected error for invalid replica port, got nil")
+	}
+}
+
+func TestMySQLTLSConfig(t *testing.T) {
+	cases := map[string]string{
+		"":            "",
+		"disable":     "",
+		"require":     "skip-verify",
+		"verify-full": "true",
+		"preferred":   "preferred",
+	}
+
+	for sslMode, expected := range cases {
+		if tls := mysqlTLSConfig(sslMode); tls != expected {
+			t.Fatalf("expected tls %q for %q, got %q", expected, sslMode, tls)
+		}
+	}
+}
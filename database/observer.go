@@ -8,21 +8,25 @@ import (
 )
 
 type databaseObserver struct {
+	name     string
 	instance *sql.DB
 }
 
 func (o databaseObserver) Close() {
-	logging.Info("waiting to safely close the database connection")
+	logging.Info("waiting to safely close the database %s connection", o.name)
 	if observer.WaitRunningTimeout() {
 		logging.Warn("WaitGroup timed out, forcing close the database connection")
 	}
 
-	logging.Info("closing database connection")
+	logging.Info("closing database %s connection", o.name)
 	if o.instance == nil {
 		logging.Error(dbNotInitializedErrorMsg)
 		return
 	}
+	closeReplicas(o.instance)
+	unregisterDialect(o.instance)
+	unregisterMigrationTarget(o.instance)
 	if err := o.instance.Close(); err != nil {
-		logging.Error("an error occurred when closing database connection: %+v", err)
+		logging.Error("an error occurred when closing database %s connection: %+v", o.name, err)
 	}
 }
@@ -0,0 +1,294 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageSize int = 20
+	MaxPageSize     int = 1000
+
+	paginationNoKeysErrorMsg   string = "keyset pagination needs at least one sort key"
+	paginationCursorErrorMsg   string = "invalid pagination cursor: %w"
+	paginationSortKeyErrorMsg  string = "sort key %s has no matching field in %s"
+	paginationCursorSizeErrMsg string = "pagination cursor has %d values, expected %d"
+)
+
+// PageRequest maps ?page=&size= when embedded in a struct decoded by
+// WebContext.DecodeQueryParams. Pages start at 1.
+type PageRequest struct {
+	Page int `form:"page" validate:"gte=0"`
+	Size int `form:"size" validate:"gte=0,lte=1000"`
+}
+
+// CursorRequest maps ?cursor=&size= for keyset pagination.
+type CursorRequest struct {
+	Cursor string `form:"cursor"`
+	Size   int    `form:"size" validate:"gte=0,lte=1000"`
+}
+
+type Page[T any] struct {
+	Items      []T   `json:"items"`
+	Page       int   `json:"page"`
+	Size       int   `json:"size"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"totalPages"`
+}
+
+type CursorPage[T any] struct {
+	Items      []T    `json:"items"`
+	Size       int    `json:"size"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	HasMore    bool   `json:"hasMore"`
+}
+
+// SortKey is a keyset column. The last key must be unique (usually the
+// primary key) and sort columns must not be NULL.
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+func Asc(column string) SortKey {
+	return SortKey{Column: column}
+}
+
+func Desc(column string) SortKey {
+	return SortKey{Column: column, Desc: true}
+}
+
+// Paginate runs base with LIMIT/OFFSET for the requested page and counts the
+// rows of base without its ordering and limits.
+func Paginate[T any](ctx context.Context, base *SelectBuilder, request PageRequest) (Page[T], error) {
+	page := max(request.Page, 1)
+	size := pageSize(request.Size)
+	result := Page[T]{Items: make([]T, 0), Page: page, Size: size}
+
+	count := base.clone()
+	count.orderBy, count.limit, count.offset, count.lock = nil, 0, 0, ""
+	countQuery, countArgs, err := count.Build()
+	if err != nil {
+		return result, err
+	}
+	countStmt := NewStatement(ctx, "SELECT COUNT(*) FROM ("+countQuery+") AS counted", countArgs...).On(base.instance)
+	if result.Total, err = QueryScalar[int64](countStmt); err != nil {
+		return result, err
+	}
+	result.TotalPages = int((result.Total + int64(size) - 1) / int64(size))
+
+	if result.Total == 0 {
+		return result, nil
+	}
+
+	stmt, err := base.clone().Limit(size).Offset((page - 1) * size).Statement(ctx)
+	if err != nil {
+		return result, err
+	}
+	if result.Items, err = Query[T](stmt); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// PaginateKeyset orders base by keys and returns the rows after the cursor,
+// with an opaque cursor for the next page when there are more rows.
+func PaginateKeyset[T any](ctx context.Context, base *SelectBuilder, request CursorRequest, keys ...SortKey) (CursorPage[T], error) {
+	size := pageSize(request.Size)
+	result := CursorPage[T]{Items: make([]T, 0), Size: size}
+
+	if len(keys) == 0 {
+		return result, errors.New(paginationNoKeysErrorMsg)
+	}
+
+	fields, err := sortKeyFields[T](keys)
+	if err != nil {
+		return result, err
+	}
+
+	query := base.clone()
+	query.orderBy = nil
+	for _, key := range keys {
+		query.OrderBy(key.order())
+	}
+
+	if request.Cursor != "" {
+		values, err := decodeCursor(request.Cursor, len(keys))
+		if err != nil {
+			return result, err
+		}
+		query.Where(keysetCondition(keys, values))
+	}
+
+	stmt, err := query.Limit(size + 1).Offset(0).Statement(ctx)
+	if err != nil {
+		return result, err
+	}
+	items, err := Query[T](stmt)
+	if err != nil {
+		return result, err
+	}
+
+	if len(items) > size {
+		items = items[:size]
+		result.HasMore = true
+		if result.NextCursor, err = encodeCursor(items[size-1], fields); err != nil {
+			return result, err
+		}
+	}
+	result.Items = items
+
+	return result, nil
+}
+
+func pageSize(size int) int {
+	if size <= 0 {
+		return DefaultPageSize
+	}
+
+	return min(size, MaxPageSize)
+}
+
+func (k SortKey) order() string {
+	if k.Desc {
+		return k.Column + " DESC"
+	}
+
+	return k.Column
+}
+
+func (s *SelectBuilder) clone() *SelectBuilder {
+	copied := *s
+	copied.columns = append([]string(nil), s.columns...)
+	copied.joins = append([]join(nil), s.joins...)
+	copied.groupBy = append([]string(nil), s.groupBy...)
+	copied.orderBy = append([]string(nil), s.orderBy...)
+
+	return &copied
+}
+
+// keysetCondition expands (k1, k2) > (v1, v2) so that each key may have its
+// own direction: k1 > v1 OR (k1 = v1 AND k2 > v2).
+func keysetCondition(keys []SortKey, values []any) Condition {
+	alternatives := make([]Condition, len(keys))
+	for i, key := range keys {
+		conditions := make([]Condition, 0, i+1)
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, Eq(keys[j].Column, values[j]))
+		}
+		if key.Desc {
+			conditions = append(conditions, Lt(key.Column, values[i]))
+		} else {
+			conditions = append(conditions, Gt(key.Column, values[i]))
+		}
+		alternatives[i] = And(conditions...)
+	}
+
+	return Or(alternatives...)
+}
+
+func sortKeyFields[T any](keys []SortKey) ([]fieldPath, error) {
+	entity := reflect.TypeFor[T]()
+	if !isStructDestination(entity) {
+		return nil, fmt.Errorf(repositoryEntityErrorMsg, entity)
+	}
+
+	byName := structFields(entity)
+	paths := make([]fieldPath, len(keys))
+	for i, key := range keys {
+		column := key.Column
+		if dot := strings.LastIndex(column, "."); dot >= 0 {
+			column = column[dot+1:]
+		}
+
+		path, ok := byName[strings.ToLower(column)]
+		if !ok {
+			return nil, fmt.Errorf(paginationSortKeyErrorMsg, key.Column, entity)
+		}
+		paths[i] = path
+	}
+
+	return paths, nil
+}
+
+// cursorValue keeps the type of times and integers, which JSON alone would
+// turn into strings and floats.
+type cursorValue struct {
+	Time  *time.Time `json:"t,omitempty"`
+	Int   *int64     `json:"i,omitempty"`
+	Value any        `json:"v"`
+}
+
+func encodeCursor[T any](item T, fields []fieldPath) (string, error) {
+	value := reflect.ValueOf(&item).Elem()
+
+	values := make([]cursorValue, len(fields))
+	for i, path := range fields {
+		raw := fieldByPath(value, path).Interface()
+		if valuer, ok := raw.(driver.Valuer); ok {
+			var err error
+			if raw, err = valuer.Value(); err != nil {
+				return "", err
+			}
+		}
+
+		switch v := reflect.ValueOf(raw); {
+		case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+			n := v.Int()
+			values[i].Int = &n
+		case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64:
+			n := int64(v.Uint())
+			values[i].Int = &n
+		default:
+			if t, ok := raw.(time.Time); ok {
+				values[i].Time = &t
+			} else {
+				values[i].Value = raw
+			}
+		}
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(cursor string, keys int) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf(paginationCursorErrorMsg, err)
+	}
+
+	var values []cursorValue
+	if err = json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf(paginationCursorErrorMsg, err)
+	}
+	if len(values) != keys {
+		return nil, fmt.Errorf(paginationCursorErrorMsg, fmt.Errorf(paginationCursorSizeErrMsg, len(values), keys))
+	}
+
+	result := make([]any, len(values))
+	for i, value := range values {
+		switch {
+		case value.Time != nil:
+			result[i] = *value.Time
+		case value.Int != nil:
+			result[i] = *value.Int
+		default:
+			result[i] = value.Value
+		}
+	}
+
+	return result, nil
+}
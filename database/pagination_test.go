@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/sdkopen/sdkopen-go/validator"
+)
+
+type paginationItem struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func seedPaginationUsers(t *testing.T, instance string, names ...string) {
+	t.Helper()
+
+	users := newUserRepository(t, instance)
+	for _, name := range names {
+		if err := users.Insert(context.Background(), &repositoryUser{Name: name, Email: name + "@example.com"}); err != nil {
+			t.Fatalf("could not seed user: %v", err)
+		}
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	newRepositoryTestInstance(t, "pagination-offset")
+	seedPaginationUsers(t, "pagination-offset", "a", "b", "c", "d", "e")
+	base := Select("id", "name").From("users").Where(NotEq("name", "e")).OrderBy("id").On("pagination-offset")
+
+	page, err := Paginate[paginationItem](context.Background(), base, PageRequest{Page: 2, Size: 3})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if page.Total != 4 || page.TotalPages != 2 || page.Page != 2 || page.Size != 3 {
+		t.Fatalf("unexpected page metadata: %+v", page)
+	}
+	if len(page.Items) != 1 || page.Items[0].Name != "d" {
+		t.Fatalf("expected only d on page 2, got %+v", page.Items)
+	}
+
+	if query, _, _ := base.BuildFor(SQLiteDialect); query != "SELECT id, name FROM users WHERE name <> ? ORDER BY id" {
+		t.Fatalf("expected base query to be left untouched, got %s", query)
+	}
+}
+
+func TestPaginate_Defaults(t *testing.T) {
+	newRepositoryTestInstance(t, "pagination-defaults")
+	base := Select("id", "name").From("users").On("pagination-defaults")
+
+	page, err := Paginate[paginationItem](context.Background(), base, PageRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if page.Page != 1 || page.Size != DefaultPageSize || page.Total != 0 || page.Items == nil {
+		t.Fatalf("unexpected empty page: %+v", page)
+	}
+
+	if size := pageSize(MaxPageSize + 1); size != MaxPageSize {
+		t.Fatalf("expected size capped at %d, got %d", MaxPageSize, size)
+	}
+}
+
+func TestPaginateKeyset(t *testing.T) {
+	newRepositoryTestInstance(t, "pagination-keyset")
+	seedPaginationUsers(t, "pagination-keyset", "b", "a", "b", "c", "a")
+	base := Select("id", "name").From("users").On("pagination-keyset")
+	ctx := context.Background()
+
+	var names []string
+	request := CursorRequest{Size: 2}
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("expected pagination to finish")
+		}
+
+		page, err := PaginateKeyset[paginationItem](ctx, base, request, Asc("name"), Desc("id"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		for _, item := range page.Items {
+			names = append(names, fmt.Sprintf("%s%d", item.Name, item.ID))
+		}
+		if !page.HasMore {
+			if page.NextCursor != "" {
+				t.Fatal("expected no cursor on the last page")
+			}
+			break
+		}
+		request.Cursor = page.NextCursor
+	}
+
+	if fmt.Sprint(names) != "[a5 a2 b3 b1 c4]" {
+		t.Fatalf("unexpected keyset order: %v", names)
+	}
+}
+
+func TestPaginateKeyset_InvalidInput(t *testing.T) {
+	base := Select("id").From("users")
+	ctx := context.Background()
+
+	if _, err := PaginateKeyset[paginationItem](ctx, base, CursorRequest{}); err == nil {
+		t.Fatal("expected error without sort keys, got nil")
+	}
+	if _, err := PaginateKeyset[paginationItem](ctx, base, CursorRequest{}, Asc("missing")); err == nil {
+		t.Fatal("expected error for unknown sort key, got nil")
+	}
+	if _, err := PaginateKeyset[paginationItem](ctx, base, CursorRequest{Cursor: "%%%"}, Asc("id")); err == nil {
+		t.Fatal("expected error for invalid cursor, got nil")
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	fields, err := sortKeyFields[paginationItem]([]SortKey{Asc("u.name"), Asc("id")})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	cursor, err := encodeCursor(paginationItem{ID: 42, Name: "Ana"}, fields)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	values, err := decodeCursor(cursor, 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if values[0] != "Ana" || values[1] != int64(42) {
+		t.Fatalf("unexpected cursor values: %#v", values)
+	}
+
+	if _, err = decodeCursor(cursor, 3); err == nil {
+		t.Fatal("expected error for cursor with wrong number of keys, got nil")
+	}
+}
+
+func TestPageRequest_DecodeQueryParams(t *testing.T) {
+	validator.Initialize()
+
+	var query struct {
+		PageRequest
+		CursorRequest
+		Name string `form:"name"`
+	}
+	values, _ := url.ParseQuery("page=3&size=50&cursor=abc&name=ana")
+	if err := validator.FormDecode(&query, values); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if query.PageRequest.Page != 3 || query.PageRequest.Size != 50 || query.Cursor != "abc" || query.Name != "ana" {
+		t.Fatalf("unexpected decoded query: %+v", query)
+	}
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/common/env"
+)
+
+const (
+	poolNegativeValueErrorMsg string = "invalid pool configuration: %s must not be negative, got %v"
+	poolIdleAboveOpenErrorMsg string = "invalid pool configuration: max idle connections (%d) must not exceed max open connections (%d)"
+)
+
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:    env.SQL_DB_MAX_OPEN_CONNS,
+		MaxIdleConns:    env.SQL_DB_MAX_IDLE_CONNS,
+		ConnMaxLifetime: env.SQL_DB_CONN_MAX_LIFETIME,
+		ConnMaxIdleTime: env.SQL_DB_CONN_MAX_IDLE_TIME,
+	}
+}
+
+func (p PoolConfig) Validate() error {
+	var errs []error
+
+	if p.MaxOpenConns < 0 {
+		errs = append(errs, fmt.Errorf(poolNegativeValueErrorMsg, "max open connections", p.MaxOpenConns))
+	}
+	if p.MaxIdleConns < 0 {
+		errs = append(errs, fmt.Errorf(poolNegativeValueErrorMsg, "max idle connections", p.MaxIdleConns))
+	}
+	if p.ConnMaxLifetime < 0 {
+		errs = append(errs, fmt.Errorf(poolNegativeValueErrorMsg, "connection max lifetime", p.ConnMaxLifetime))
+	}
+	if p.ConnMaxIdleTime < 0 {
+		errs = append(errs, fmt.Errorf(poolNegativeValueErrorMsg, "connection max idle time", p.ConnMaxIdleTime))
+	}
+	if p.MaxOpenConns > 0 && p.MaxIdleConns > p.MaxOpenConns {
+		errs = append(errs, fmt.Errorf(poolIdleAboveOpenErrorMsg, p.MaxIdleConns, p.MaxOpenConns))
+	}
+
+	return errors.Join(errs...)
+}
+
+// clampIdle lowers a default MaxIdleConns to MaxOpenConns, as database/sql
+// does, so that only setting a small MaxOpenConns is not a conflict.
+func (p PoolConfig) clampIdle() PoolConfig {
+	if p.MaxOpenConns > 0 && p.MaxIdleConns > p.MaxOpenConns {
+		p.MaxIdleConns = p.MaxOpenConns
+	}
+
+	return p
+}
+
+func (p PoolConfig) apply(db *sql.DB) {
+	db.SetMaxOpenConns(p.MaxOpenConns)
+	db.SetMaxIdleConns(p.MaxIdleConns)
+	db.SetConnMaxLifetime(p.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/common/env"
+)
+
+func TestDefaultPoolConfig(t *testing.T) {
+	env.SQL_DB_MAX_OPEN_CONNS = 20
+	env.SQL_DB_MAX_IDLE_CONNS = 4
+	env.SQL_DB_CONN_MAX_LIFETIME = time.Hour
+	env.SQL_DB_CONN_MAX_IDLE_TIME = time.Minute
+
+	pool := DefaultPoolConfig()
+
+	if pool.MaxOpenConns != 20 {
+		t.Fatalf("expected MaxOpenConns=20, got %d", pool.MaxOpenConns)
+	}
+	if pool.MaxIdleConns != 4 {
+		t.Fatalf("expected MaxIdleConns=4, got %d", pool.MaxIdleConns)
+	}
+	if pool.ConnMaxLifetime != time.Hour {
+		t.Fatalf("expected ConnMaxLifetime=1h, got %v", pool.ConnMaxLifetime)
+	}
+	if pool.ConnMaxIdleTime != time.Minute {
+		t.Fatalf("expected ConnMaxIdleTime=1m, got %v", pool.ConnMaxIdleTime)
+	}
+}
+
+func TestPoolConfig_Validate_Valid(t *testing.T) {
+	pool := PoolConfig{MaxOpenConns: 10, MaxIdleConns: 5, ConnMaxLifetime: time.Minute}
+
+	if err := pool.Validate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestPoolConfig_Validate_UnlimitedOpenConns(t *testing.T) {
+	pool := PoolConfig{MaxOpenConns: 0, MaxIdleConns: 5}
+
+	if err := pool.Validate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestPoolConfig_Validate_NegativeValues(t *testing.T) {
+	pool := PoolConfig{MaxOpenConns: -1, ConnMaxIdleTime: -time.Second}
+
+	if err := pool.Validate(); err == nil {
+		t.Fatal("expected error for negative values, got nil")
+	}
+}
+
+func TestPoolConfig_Validate_IdleAboveOpen(t *testing.T) {
+	pool := PoolConfig{MaxOpenConns: 5, MaxIdleConns: 10}
+
+	if err := pool.Validate(); err == nil {
+		t.Fatal("expected error for idle connections above open connections, got nil")
+	}
+}
+
+func TestPoolConfig_Apply(t *testing.T) {
+	db, _ := newFakeDB(t, nil)
+
+	PoolConfig{MaxOpenConns: 7, MaxIdleConns: 3}.apply(db)
+
+	if stats := db.Stats(); stats.MaxOpenConnections != 7 {
+		t.Fatalf("expected MaxOpenConnections=7, got %d", stats.MaxOpenConnections)
+	}
+}
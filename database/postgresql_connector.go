@@ -13,46 +13,60 @@ import (
 const (
 	defaultDriver        string = "postgres"
 	defaultConnectionURI string = "host=%s port=%d user=%s password=%s dbname=%s sslmode=%s"
-	dbMigrationErrorMsg  string = "An error occurred when validate database migrations: %+v"
 )
 
 type PostgresqlConnector struct {
+	driver   string
 	host     string
 	port     int
 	database string
 	username string
 	password string
 	sslMode  string
+	connectorConfig
 }
 
-func NewDefaultPostgresqlConnector() *PostgresqlConnector {
-	return &PostgresqlConnector{
-		host:     env.SQL_DB_URL,
-		port:     env.SQL_DB_PORT,
-		database: env.SQL_DB_NAME,
-		username: env.SQL_DB_USERNAME,
-		password: env.SQL_DB_PASSWORD,
-		sslMode:  env.SQL_DB_SSL_MODE,
-	}
-}
+type PostgresqlOption = ConnectorOption
 
-func (c *PostgresqlConnector) Connect() *sql.DB {
-	var connectionString = c.getConnectionURI()
+func NewDefaultPostgresqlConnector(opts ...PostgresqlOption) *PostgresqlConnector {
+	return newPostgresqlConnector(env.DefaultSqlDB(), opts)
+}
 
-	db, err := sql.Open(env.SQL_DB_DRIVER, connectionString)
+func NewPrefixedPostgresqlConnector(prefix string, opts ...PostgresqlOption) *PostgresqlConnector {
+	cfg, err := env.LoadSqlDB(prefix)
 	if err != nil {
-		logging.Fatal("an error occurred while trying to connect to the %s database: %+v", defaultDriver, err)
+		logging.Fatal("%s", err.Error())
 	}
 
-	if err = db.Ping(); err != nil {
-		logging.Fatal("an error occurred while trying to connect to the %s database: %+v", defaultDriver, err)
+	return newPostgresqlConnector(cfg, opts)
+}
+
+func newPostgresqlConnector(cfg env.SqlDB, opts []PostgresqlOption) *PostgresqlConnector {
+	driver := cfg.Driver
+	if driver == "" {
+		driver = defaultDriver
 	}
 
-	if err := migration(db); err != nil {
-		logging.Fatal(dbMigrationErrorMsg, err)
+	return &PostgresqlConnector{
+		driver:          driver,
+		host:            cfg.Url,
+		port:            cfg.Port,
+		database:        cfg.Name,
+		username:        cfg.Username,
+		password:        cfg.Password,
+		sslMode:         cfg.SslMode,
+		connectorConfig: newConnectorConfig(cfg, opts),
 	}
+}
 
-	return db
+func (c *PostgresqlConnector) Connect() *sql.DB {
+	return c.open(connectTarget{
+		driver:     c.driver,
+		dialect:    PostgresDialect,
+		dsn:        c.getConnectionURI(),
+		address:    fmt.Sprintf("%s:%d", c.host, c.port),
+		replicaDSN: c.getReplicaConnectionURI,
+	})
 }
 
 func (c *PostgresqlConnector) getConnectionURI() string {
@@ -65,6 +79,27 @@ func (c *PostgresqlConnector) getConnectionURI() string {
 		c.sslMode)
 }
 
+func (c *PostgresqlConnector) getReplicaConnectionURI(address string) (string, error) {
+	host, port, err := splitHostPort(address, c.port)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(defaultConnectionURI,
+		host,
+		port,
+		c.username,
+		c.password,
+		c.database,
+		c.sslMode), nil
+}
+
 func Postgresql() *sql.DB {
 	return NewDefaultPostgresqlConnector().Connect()
 }
+
+func PostgresqlWithPrefix(prefix string) func() *sql.DB {
+	return func() *sql.DB {
+		return NewPrefixedPostgresqlConnector(prefix).Connect()
+	}
+}
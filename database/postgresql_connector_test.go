@@ -2,8 +2,11 @@ package database
 
 import (
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/sdkopen/sdkopen-go/common/env"
+	"github.com/sdkopen/sdkopen-go/common/retry"
 )
 
 func TestNewDefaultPostgresqlConnector(t *testing.T) {
@@ -36,6 +39,87 @@ func TestNewDefaultPostgresqlConnector(t *testing.T) {
 	}
 }
 
+func TestNewDefaultPostgresqlConnector_PoolOptions(t *testing.T) {
+	env.SQL_DB_MAX_OPEN_CONNS = 10
+	env.SQL_DB_MAX_IDLE_CONNS = 5
+
+	connector := NewDefaultPostgresqlConnector(
+		WithMaxOpenConns(50),
+		WithConnMaxLifetime(time.Hour),
+		WithConnMaxIdleTime(time.Minute),
+	)
+
+	if connector.pool.MaxOpenConns != 50 {
+		t.Fatalf("expected MaxOpenConns=50, got %d", connector.pool.MaxOpenConns)
+	}
+	if connector.pool.MaxIdleConns != 5 {
+		t.Fatalf("expected MaxIdleConns=5 (from env), got %d", connector.pool.MaxIdleConns)
+	}
+	if connector.pool.ConnMaxLifetime != time.Hour {
+		t.Fatalf("expected ConnMaxLifetime=1h, got %v", connector.pool.ConnMaxLifetime)
+	}
+	if connector.pool.ConnMaxIdleTime != time.Minute {
+		t.Fatalf("expected ConnMaxIdleTime=1m, got %v", connector.pool.ConnMaxIdleTime)
+	}
+}
+
+func TestNewDefaultPostgresqlConnector_OnlyMaxOpenConns(t *testing.T) {
+	env.SQL_DB_MAX_OPEN_CONNS = 10
+	env.SQL_DB_MAX_IDLE_CONNS = 5
+
+	connector := NewDefaultPostgresqlConnector(WithMaxOpenConns(2))
+
+	if connector.pool.MaxIdleConns != 2 {
+		t.Fatalf("expected default MaxIdleConns clamped to 2, got %d", connector.pool.MaxIdleConns)
+	}
+	if err := connector.pool.Validate(); err != nil {
+		t.Fatalf("expected valid pool configuration, got %v", err)
+	}
+}
+
+func TestNewDefaultPostgresqlConnector_ExplicitIdleAboveOpen(t *testing.T) {
+	connector := NewDefaultPostgresqlConnector(WithMaxOpenConns(2), WithMaxIdleConns(5))
+
+	if err := connector.pool.Validate(); err == nil {
+		t.Fatal("expected error for explicit idle connections above open connections, got nil")
+	}
+}
+
+func TestNewDefaultPostgresqlConnector_WithPoolConfig(t *testing.T) {
+	pool := PoolConfig{MaxOpenConns: 3, MaxIdleConns: 1}
+
+	connector := NewDefaultPostgresqlConnector(WithPoolConfig(pool), WithMaxIdleConns(2))
+
+	if connector.pool.MaxOpenConns != 3 || connector.pool.MaxIdleConns != 2 {
+		t.Fatalf("unexpected pool configuration: %+v", connector.pool)
+	}
+}
+
+func TestNewPrefixedPostgresqlConnector(t *testing.T) {
+	t.Setenv("REPORTING_SQL_DB_URL", "reporting-host")
+	t.Setenv("REPORTING_SQL_DB_NAME", "reports")
+	t.Setenv("REPORTING_SQL_DB_EXEC_MIGRATION", "true")
+	t.Setenv("REPORTING_SQL_DB_MIGRATION_SOURCE_URL", "/migrations/reporting")
+
+	connector := NewPrefixedPostgresqlConnector("REPORTING", WithMaxOpenConns(2))
+
+	if connector.host != "reporting-host" {
+		t.Fatalf("expected host=reporting-host, got %s", connector.host)
+	}
+	if connector.database != "reports" {
+		t.Fatalf("expected database=reports, got %s", connector.database)
+	}
+	if !connector.migration.enabled || connector.migration.sourceUrl != "/migrations/reporting" {
+		t.Fatalf("unexpected migration config: %+v", connector.migration)
+	}
+	if connector.migration.databaseName != "reports" {
+		t.Fatalf("expected migration databaseName=reports, got %s", connector.migration.databaseName)
+	}
+	if connector.pool.MaxOpenConns != 2 {
+		t.Fatalf("expected MaxOpenConns=2, got %d", connector.pool.MaxOpenConns)
+	}
+}
+
 func TestPostgresqlConnector_GetConnectionURI(t *testing.T) {
 	connector := &PostgresqlConnector{
 		host:     "db.example.com",
@@ -71,3 +155,87 @@ func TestPostgresqlConnector_GetConnectionURI_DefaultValues(t *testing.T) {
 		t.Fatalf("expected:\n%s\ngot:\n%s", expected, uri)
 	}
 }
+
+func TestPostgresqlConnector_GetReplicaConnectionURI(t *testing.T) {
+	connector := &PostgresqlConnector{
+		port:     5432,
+		username: "admin",
+		password: "secret",
+		database: "mydb",
+		sslMode:  "require",
+	}
+
+	uri, err := connector.getReplicaConnectionURI("replica-1:5433")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := "host=replica-1 port=5433 user=admin password=secret dbname=mydb sslmode=require"
+	if uri != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, uri)
+	}
+
+	uri, _ = connector.getReplicaConnectionURI("replica-2")
+	expected = "host=replica-2 port=5432 user=admin password=secret dbname=mydb sslmode=require"
+	if uri != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, uri)
+	}
+
+	if _, err = connector.getReplicaConnectionURI("replica-3:abc"); err == nil {
+		t.Fatal("expected error for invalid replica port, got nil")
+	}
+}
+
+func TestNewDefaultPostgresqlConnector_Replicas(t *testing.T) {
+	env.SQL_DB_REPLICA_URLS = "replica-1:5433, replica-2 ,"
+	defer func() { env.SQL_DB_REPLICA_URLS = "" }()
+
+	connector := NewDefaultPostgresqlConnector()
+	if len(connector.replicas) != 2 || connector.replicas[0] != "replica-1:5433" || connector.replicas[1] != "replica-2" {
+		t.Fatalf("unexpected replicas: %v", connector.replicas)
+	}
+
+	connector = NewDefaultPostgresqlConnector(WithReplicas("replica-3"), WithReplicaHealthCheck(time.Second))
+	if len(connector.replicas) != 1 || connector.replicas[0] != "replica-3" {
+		t.Fatalf("unexpected replicas: %v", connector.replicas)
+	}
+	if connector.replicaHC != time.Second {
+		t.Fatalf("expected replica health check=1s, got %v", connector.replicaHC)
+	}
+}
+
+func TestNewDefaultPostgresqlConnector_WithConnectRetry(t *testing.T) {
+	cfg := retry.Config{MaxAttempts: 5, InitialInterval: time.Second}
+
+	connector := NewDefaultPostgresqlConnector(WithConnectRetry(cfg))
+
+	if connector.retry != cfg {
+		t.Fatalf("expected retry config %+v, got %+v", cfg, connector.retry)
+	}
+}
+
+func TestNewDefaultPostgresqlConnector_WithAutoMigration(t *testing.T) {
+	env.SQL_DB_EXEC_MIGRATION = true
+	defer func() { env.SQL_DB_EXEC_MIGRATION = false }()
+
+	connector := NewDefaultPostgresqlConnector(WithAutoMigration(false))
+
+	if connector.migration.enabled {
+		t.Fatal("expected auto migration to be disabled by option")
+	}
+}
+
+func TestNewDefaultPostgresqlConnector_WithMigrations(t *testing.T) {
+	fsys := fstest.MapFS{}
+
+	connector := NewDefaultPostgresqlConnector(WithMigrations(
+		WithMigrationFS(fsys, "migrations"),
+		WithMigrationDatabaseName("other"),
+	))
+
+	if connector.migration.fsys == nil || connector.migration.fsDir != "migrations" {
+		t.Fatalf("expected embedded migrations to be configured, got %+v", connector.migration)
+	}
+	if connector.migration.databaseName != "other" {
+		t.Fatalf("expected databaseName=other, got %s", connector.migration.databaseName)
+	}
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"database/sql"
+)
+
+func Query[T any](s *Statement) ([]T, error) {
+	return QueryInInstance[T](s, readInstance(s.ctx, Instance(s.instance)))
+}
+
+func QueryInInstance[T any](s *Statement, instance *sql.DB) ([]T, error) {
+	var result []T
+	err := s.queryInInstance(instance, func(rows *sql.Rows) error {
+		var err error
+		result, err = scanRows[T](rows)
+		return err
+	})
+
+	return result, err
+}
+
+func QueryOne[T any](s *Statement) (T, error) {
+	return QueryOneInInstance[T](s, readInstance(s.ctx, Instance(s.instance)))
+}
+
+func QueryOneInInstance[T any](s *Statement, instance *sql.DB) (T, error) {
+	var result T
+	err := s.queryInInstance(instance, func(rows *sql.Rows) error {
+		columns, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			return sql.ErrNoRows
+		}
+
+		return scanRow(rows, columns, &result)
+	})
+
+	return result, err
+}
+
+func QueryScalar[T any](s *Statement) (T, error) {
+	return QueryScalarInInstance[T](s, readInstance(s.ctx, Instance(s.instance)))
+}
+
+func QueryScalarInInstance[T any](s *Statement, instance *sql.DB) (T, error) {
+	var result T
+	err := s.queryInInstance(instance, func(rows *sql.Rows) error {
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			return sql.ErrNoRows
+		}
+
+		return rows.Scan(&result)
+	})
+
+	return result, err
+}
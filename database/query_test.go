@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+func usersHandler(string, []driver.Value) fakeResponse {
+	return fakeResponse{
+		columns: []string{"id", "name", "nickname", "email", "updated_by"},
+		rows: [][]driver.Value{
+			{int64(1), "Alice", "ali", "alice@example.com", "admin"},
+			{int64(2), "Bob", nil, nil, "admin"},
+		},
+	}
+}
+
+func TestQueryInInstance_MapsRows(t *testing.T) {
+	db, _ := newFakeDB(t, usersHandler)
+
+	users, err := QueryInInstance[scannerUser](NewStatement(context.Background(), "SELECT * FROM users"), db)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(users))
+	}
+	if users[0].ID != 1 || users[0].Name != "Alice" {
+		t.Fatalf("unexpected first user: %+v", users[0])
+	}
+	if users[0].Nickname == nil || *users[0].Nickname != "ali" {
+		t.Fatalf("expected nickname=ali, got %v", users[0].Nickname)
+	}
+	if users[1].Nickname != nil {
+		t.Fatalf("expected nil nickname, got %v", *users[1].Nickname)
+	}
+	if users[1].Email.Valid {
+		t.Fatal("expected invalid email for NULL value")
+	}
+	if users[1].UpdatedBy != "admin" {
+		t.Fatalf("expected updated_by=admin, got %s", users[1].UpdatedBy)
+	}
+}
+
+func TestQueryInInstance_EmptyResult(t *testing.T) {
+	db, _ := newFakeDB(t, func(string, []driver.Value) fakeResponse {
+		return fakeResponse{columns: []string{"id"}}
+	})
+
+	users, err := QueryInInstance[scannerUser](NewStatement(context.Background(), "SELECT id FROM users"), db)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if users == nil || len(users) != 0 {
+		t.Fatalf("expected empty non-nil slice, got %v", users)
+	}
+}
+
+func TestQueryInInstance_NilInstance(t *testing.T) {
+	_, err := QueryInInstance[scannerUser](NewStatement(context.Background(), "SELECT 1"), nil)
+	if err == nil || err.Error() != dbNotInitializedErrorMsg {
+		t.Fatalf("expected '%s', got %v", dbNotInitializedErrorMsg, err)
+	}
+}
+
+func TestQueryOneInInstance_ReturnsFirstRow(t *testing.T) {
+	db, _ := newFakeDB(t, usersHandler)
+
+	user, err := QueryOneInInstance[scannerUser](NewStatement(context.Background(), "SELECT * FROM users WHERE id = $1", 1), db)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if user.Name != "Alice" {
+		t.Fatalf("expected name=Alice, got %s", user.Name)
+	}
+}
+
+func TestQueryOneInInstance_NoRows(t *testing.T) {
+	db, _ := newFakeDB(t, func(string, []driver.Value) fakeResponse {
+		return fakeResponse{columns: []string{"id"}}
+	})
+
+	_, err := QueryOneInInstance[scannerUser](NewStatement(context.Background(), "SELECT id FROM users"), db)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestQueryScalarInInstance(t *testing.T) {
+	db, _ := newFakeDB(t, func(string, []driver.Value) fakeResponse {
+		return fakeResponse{columns: []string{"count"}, rows: [][]driver.Value{{int64(42)}}}
+	})
+
+	count, err := QueryScalarInInstance[int](NewStatement(context.Background(), "SELECT COUNT(*) FROM users"), db)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if count != 42 {
+		t.Fatalf("expected 42, got %d", count)
+	}
+}
+
+func TestQueryInInstance_TimeDestination(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 30, 0, 0, time.UTC)
+	db, _ := newFakeDB(t, func(string, []driver.Value) fakeResponse {
+		return fakeResponse{columns: []string{"now"}, rows: [][]driver.Value{{now}}}
+	})
+
+	times, err := QueryInInstance[time.Time](NewStatement(context.Background(), "SELECT CURRENT_TIMESTAMP AS now"), db)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(times) != 1 || !times[0].Equal(now) {
+		t.Fatalf("expected [%s], got %v", now, times)
+	}
+
+	single, err := QueryOneInInstance[time.Time](NewStatement(context.Background(), "SELECT CURRENT_TIMESTAMP AS now"), db)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !single.Equal(now) {
+		t.Fatalf("expected %s, got %s", now, single)
+	}
+}
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/logging"
+)
+
+const (
+	sqlPrimaryContext contextKey = "SqlPrimaryContext"
+
+	replicaHealthCheckTimeout time.Duration = 5 * time.Second
+
+	replicaEvictedMsg    string = "database replica %s evicted, health check failed: %v"
+	replicaRestoredMsg   string = "database replica %s restored"
+	replicaCloseErrorMsg string = "an error occurred when closing database replica %s: %+v"
+)
+
+var (
+	replicaSets  = map[*sql.DB]*replicaSet{}
+	replicaMutex sync.RWMutex
+)
+
+type replica struct {
+	name    string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+type replicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+	done     chan struct{}
+}
+
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sqlPrimaryContext, true)
+}
+
+func registerReplicas(primary *sql.DB, replicas []*replica, interval time.Duration) {
+	set := &replicaSet{replicas: replicas, done: make(chan struct{})}
+
+	replicaMutex.Lock()
+	replicaSets[primary] = set
+	replicaMutex.Unlock()
+
+	if interval > 0 {
+		go set.watch(interval)
+	}
+}
+
+func readInstance(ctx context.Context, primary *sql.DB) *sql.DB {
+	if primary == nil || transactionFromContext(ctx) != nil {
+		return primary
+	}
+	if forced, _ := ctx.Value(sqlPrimaryContext).(bool); forced {
+		return primary
+	}
+
+	replicaMutex.RLock()
+	set := replicaSets[primary]
+	replicaMutex.RUnlock()
+
+	if set == nil {
+		return primary
+	}
+
+	if r := set.pick(); r != nil {
+		return r.db
+	}
+
+	return primary
+}
+
+func (s *replicaSet) pick() *replica {
+	total := len(s.replicas)
+	start := int(s.next.Add(1) - 1)
+
+	for i := 0; i < total; i++ {
+		if r := s.replicas[(start+i)%total]; r.healthy.Load() {
+			return r
+		}
+	}
+
+	return nil
+}
+
+func (s *replicaSet) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.checkHealth()
+		}
+	}
+}
+
+func (s *replicaSet) checkHealth() {
+	for _, r := range s.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), replicaHealthCheckTimeout)
+		err := r.db.PingContext(ctx)
+		cancel()
+
+		if err != nil && r.healthy.Swap(false) {
+			logging.Warn(replicaEvictedMsg, r.name, err)
+		}
+		if err == nil && !r.healthy.Swap(true) {
+			logging.Info(replicaRestoredMsg, r.name)
+		}
+	}
+}
+
+func closeReplicas(primary *sql.DB) {
+	replicaMutex.Lock()
+	set := replicaSets[primary]
+	delete(replicaSets, primary)
+	replicaMutex.Unlock()
+
+	if set == nil {
+		return
+	}
+
+	close(set.done)
+	for _, r := range set.replicas {
+		logging.Info("closing database replica %s connection", r.name)
+		if err := r.db.Close(); err != nil {
+			logging.Error(replicaCloseErrorMsg, r.name, err)
+		}
+	}
+}
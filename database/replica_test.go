@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func newTestReplica(t *testing.T, name string, healthy bool) (*replica, *fakeDriver) {
+	db, drv := newFakeDB(t, nil)
+	r := &replica{name: name, db: db}
+	r.healthy.Store(healthy)
+	return r, drv
+}
+
+func registerTestReplicas(t *testing.T, primary *sql.DB, replicas ...*replica) *replicaSet {
+	registerReplicas(primary, replicas, 0)
+	t.Cleanup(func() { closeReplicas(primary) })
+
+	replicaMutex.RLock()
+	defer replicaMutex.RUnlock()
+	return replicaSets[primary]
+}
+
+func TestReadInstance_WithoutReplicas(t *testing.T) {
+	primary, _ := newFakeDB(t, nil)
+
+	if readInstance(context.Background(), primary) != primary {
+		t.Fatal("expected primary when no replicas are registered")
+	}
+}
+
+func TestReadInstance_RoundRobin(t *testing.T) {
+	primary, _ := newFakeDB(t, nil)
+	r1, _ := newTestReplica(t, "replica-1", true)
+	r2, _ := newTestReplica(t, "replica-2", true)
+	registerTestReplicas(t, primary, r1, r2)
+
+	first := readInstance(context.Background(), primary)
+	second := readInstance(context.Background(), primary)
+	third := readInstance(context.Background(), primary)
+
+	if first != r1.db || second != r2.db || third != r1.db {
+		t.Fatal("expected reads to alternate between replicas")
+	}
+}
+
+func TestReadInstance_SkipsUnhealthyReplicas(t *testing.T) {
+	primary, _ := newFakeDB(t, nil)
+	r1, _ := newTestReplica(t, "replica-1", false)
+	r2, _ := newTestReplica(t, "replica-2", true)
+	registerTestReplicas(t, primary, r1, r2)
+
+	for i := 0; i < 3; i++ {
+		if readInstance(context.Background(), primary) != r2.db {
+			t.Fatal("expected reads to go to the healthy replica")
+		}
+	}
+}
+
+func TestReadInstance_FallsBackToPrimary(t *testing.T) {
+	primary, _ := newFakeDB(t, nil)
+	r1, _ := newTestReplica(t, "replica-1", false)
+	registerTestReplicas(t, primary, r1)
+
+	if readInstance(context.Background(), primary) != primary {
+		t.Fatal("expected primary when all replicas are evicted")
+	}
+}
+
+func TestReadInstance_ForcedPrimary(t *testing.T) {
+	primary, _ := newFakeDB(t, nil)
+	r1, _ := newTestReplica(t, "replica-1", true)
+	registerTestReplicas(t, primary, r1)
+
+	if readInstance(WithPrimary(context.Background()), primary) != primary {
+		t.Fatal("expected primary when WithPrimary is set")
+	}
+}
+
+func TestReadInstance_InsideTransaction(t *testing.T) {
+	primary, _ := newFakeDB(t, nil)
+	r1, _ := newTestReplica(t, "replica-1", true)
+	registerTestReplicas(t, primary, r1)
+
+	err := WithTransactionInInstance(context.Background(), primary, func(ctx context.Context) error {
+		if readInstance(ctx, primary) != primary {
+			t.Fatal("expected primary inside a transaction")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestReplicaSet_CheckHealth(t *testing.T) {
+	primary, _ := newFakeDB(t, nil)
+	r1, drv := newTestReplica(t, "replica-1", true)
+	set := registerTestReplicas(t, primary, r1)
+
+	drv.SetPingError(errors.New("connection refused"))
+	set.checkHealth()
+	if r1.healthy.Load() {
+		t.Fatal("expected replica to be evicted after failed health check")
+	}
+
+	drv.SetPingError(nil)
+	set.checkHealth()
+	if !r1.healthy.Load() {
+		t.Fatal("expected replica to be restored after successful health check")
+	}
+}
+
+func TestQuery_RoutesToReplica(t *testing.T) {
+	primary, primaryDrv := newFakeDB(t, nil)
+	r1, replicaDrv := newTestReplica(t, "replica-1", true)
+	registerTestReplicas(t, primary, r1)
+
+	dbInstance = primary
+	t.Cleanup(func() { dbInstance = nil })
+
+	if _, err := Query[int64](NewStatement(context.Background(), "SELECT id FROM users")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := NewStatement(context.Background(), "DELETE FROM users").Execute(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if events := replicaDrv.Events(); len(events) != 1 || events[0] != "SELECT id FROM users" {
+		t.Fatalf("expected read on replica, got %v", events)
+	}
+	if events := primaryDrv.Events(); len(events) != 1 || events[0] != "DELETE FROM users" {
+		t.Fatalf("expected write on primary, got %v", events)
+	}
+}
@@ -0,0 +1,295 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	repositoryPkOption         string = "pk"
+	repositoryAutoOption       string = "auto"
+	repositorySoftDeleteOption string = "softdelete"
+
+	repositoryEntityErrorMsg string = "repository entity %s must be a struct"
+	repositoryNoPkErrorMsg   string = "repository entity %s has no primary key, tag a field with `db:\"<column>,pk\"` or name it id"
+	repositoryNoTableMsg     string = "repository entity %s has no table name, implement TableName() or use WithTableName"
+	repositoryFilterErrorMsg string = "filter column %s has no matching field in %s"
+	repositoryInsertIDMsg    string = "could not assign generated id %d to %s field"
+)
+
+// Filter matches columns by equality, a nil value matches NULL.
+type Filter map[string]any
+
+type TableNamer interface {
+	TableName() string
+}
+
+type RepositoryOption func(*repositoryConfig)
+
+type repositoryConfig struct {
+	table string
+}
+
+// WithTableName sets the table of the repository, taking precedence over the
+// TableName method of the entity.
+func WithTableName(name string) RepositoryOption {
+	return func(c *repositoryConfig) {
+		c.table = name
+	}
+}
+
+type Repository[T any, ID any] struct {
+	table      string
+	instance   string
+	entity     reflect.Type
+	columns    []structField
+	pk         structField
+	softDelete *structField
+}
+
+func NewRepository[T any, ID any](opts ...RepositoryOption) (*Repository[T, ID], error) {
+	entity := reflect.TypeFor[T]()
+	if !isStructDestination(entity) {
+		return nil, fmt.Errorf(repositoryEntityErrorMsg, entity)
+	}
+
+	config := repositoryConfig{}
+	if namer, ok := reflect.New(entity).Interface().(TableNamer); ok {
+		config.table = namer.TableName()
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.table == "" {
+		return nil, fmt.Errorf(repositoryNoTableMsg, entity)
+	}
+
+	repository := &Repository[T, ID]{
+		table:   config.table,
+		entity:  entity,
+		columns: describeStruct(entity).fields,
+	}
+
+	pkFound := false
+	for i, column := range repository.columns {
+		if column.hasOption(repositoryPkOption) || (!pkFound && column.name == defaultReturningColumn) {
+			repository.pk, pkFound = column, true
+		}
+		if column.hasOption(repositorySoftDeleteOption) {
+			repository.softDelete = &repository.columns[i]
+		}
+	}
+	if !pkFound {
+		return nil, fmt.Errorf(repositoryNoPkErrorMsg, entity)
+	}
+
+	return repository, nil
+}
+
+// On returns a copy of the repository bound to the named instance.
+func (r *Repository[T, ID]) On(instance string) *Repository[T, ID] {
+	copied := *r
+	copied.instance = instance
+	return &copied
+}
+
+func (r *Repository[T, ID]) FindByID(ctx context.Context, id ID) (T, error) {
+	db := Instance(r.instance)
+	b := &bindings{dialect: dialectOf(db)}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", r.columnList(b.dialect), b.dialect.QuoteIdentifier(r.table), r.byID(b, id))
+
+	return QueryOneInInstance[T](NewStatement(ctx, query, b.args...), readInstance(ctx, db))
+}
+
+func (r *Repository[T, ID]) FindAll(ctx context.Context, filter Filter) ([]T, error) {
+	db := Instance(r.instance)
+	b := &bindings{dialect: dialectOf(db)}
+	where, err := r.where(b, filter)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s", r.columnList(b.dialect), b.dialect.QuoteIdentifier(r.table), where, b.dialect.QuoteIdentifier(r.pk.name))
+
+	return QueryInInstance[T](NewStatement(ctx, query, b.args...), readInstance(ctx, db))
+}
+
+func (r *Repository[T, ID]) Count(ctx context.Context, filter Filter) (int64, error) {
+	db := Instance(r.instance)
+	b := &bindings{dialect: dialectOf(db)}
+	where, err := r.where(b, filter)
+	if err != nil {
+		return 0, err
+	}
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", b.dialect.QuoteIdentifier(r.table), where)
+
+	return QueryScalarInInstance[int64](NewStatement(ctx, query, b.args...), readInstance(ctx, db))
+}
+
+func (r *Repository[T, ID]) Exists(ctx context.Context, id ID) (bool, error) {
+	db := Instance(r.instance)
+	b := &bindings{dialect: dialectOf(db)}
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", b.dialect.QuoteIdentifier(r.table), r.byID(b, id))
+
+	count, err := QueryScalarInInstance[int64](NewStatement(ctx, query, b.args...), readInstance(ctx, db))
+	return count > 0, err
+}
+
+// Insert writes entity and, for a primary key tagged `auto`, stores the
+// generated id back into it.
+func (r *Repository[T, ID]) Insert(ctx context.Context, entity *T) error {
+	db := Instance(r.instance)
+	dialect := dialectOf(db)
+	b := &bindings{dialect: dialect}
+	value := reflect.ValueOf(entity).Elem()
+	auto := r.pk.hasOption(repositoryAutoOption)
+
+	columns := make([]string, 0, len(r.columns))
+	placeholders := make([]string, 0, len(r.columns))
+	for _, column := range r.columns {
+		if auto && column.name == r.pk.name {
+			continue
+		}
+		columns = append(columns, dialect.QuoteIdentifier(column.name))
+		placeholders = append(placeholders, b.bind(fieldByPath(value, column.path).Interface()))
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", dialect.QuoteIdentifier(r.table), strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+	stmt := NewStatement(ctx, query, b.args...)
+	if !auto {
+		return stmt.ExecuteInInstance(db)
+	}
+
+	target := fieldByPath(value, r.pk.path)
+	if dialect == MySQLDialect {
+		result, err := stmt.ExecuteWithResultInInstance(db)
+		if err != nil {
+			return err
+		}
+		return assignInsertID(target, result.LastInsertID)
+	}
+
+	return stmt.withReturning([]string{dialect.QuoteIdentifier(r.pk.name)}).queryInInstance(db, func(rows *sql.Rows) error {
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			return sql.ErrNoRows
+		}
+		return rows.Scan(target.Addr().Interface())
+	})
+}
+
+// Update writes every column but the primary key and the soft delete marker,
+// returning sql.ErrNoRows when no live row matches.
+func (r *Repository[T, ID]) Update(ctx context.Context, entity *T) error {
+	db := Instance(r.instance)
+	b := &bindings{dialect: dialectOf(db)}
+	value := reflect.ValueOf(entity).Elem()
+
+	assignments := make([]string, 0, len(r.columns))
+	for _, column := range r.columns {
+		if column.name == r.pk.name || (r.softDelete != nil && column.name == r.softDelete.name) {
+			continue
+		}
+		assignments = append(assignments, b.dialect.QuoteIdentifier(column.name)+" = "+b.bind(fieldByPath(value, column.path).Interface()))
+	}
+
+	id := fieldByPath(value, r.pk.path).Interface()
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", b.dialect.QuoteIdentifier(r.table), strings.Join(assignments, ", "), r.byID(b, id))
+
+	return r.expectRows(NewStatement(ctx, query, b.args...).ExecuteWithResultInInstance(db))
+}
+
+// Delete stamps the soft delete column when the entity has one and removes
+// the row otherwise.
+func (r *Repository[T, ID]) Delete(ctx context.Context, id ID) error {
+	db := Instance(r.instance)
+	b := &bindings{dialect: dialectOf(db)}
+
+	table := b.dialect.QuoteIdentifier(r.table)
+	condition := r.byID(b, id)
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s", table, condition)
+	if r.softDelete != nil {
+		query = fmt.Sprintf("UPDATE %s SET %s = CURRENT_TIMESTAMP WHERE %s", table, b.dialect.QuoteIdentifier(r.softDelete.name), condition)
+	}
+
+	return r.expectRows(NewStatement(ctx, query, b.args...).ExecuteWithResultInInstance(db))
+}
+
+func (r *Repository[T, ID]) columnList(dialect Dialect) string {
+	names := make([]string, len(r.columns))
+	for i, column := range r.columns {
+		names[i] = dialect.QuoteIdentifier(column.name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func (r *Repository[T, ID]) byID(b *bindings, id any) string {
+	condition := b.dialect.QuoteIdentifier(r.pk.name) + " = " + b.bind(id)
+	if r.softDelete != nil {
+		condition += " AND " + b.dialect.QuoteIdentifier(r.softDelete.name) + " IS NULL"
+	}
+
+	return condition
+}
+
+func (r *Repository[T, ID]) where(b *bindings, filter Filter) (string, error) {
+	names := make([]string, 0, len(filter))
+	for name := range filter {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	conditions := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		column := strings.ToLower(name)
+		if _, ok := describeStruct(r.entity).byName[column]; !ok {
+			return "", fmt.Errorf(repositoryFilterErrorMsg, name, r.entity)
+		}
+
+		if value := filter[name]; value == nil {
+			conditions = append(conditions, b.dialect.QuoteIdentifier(column)+" IS NULL")
+		} else {
+			conditions = append(conditions, b.dialect.QuoteIdentifier(column)+" = "+b.bind(value))
+		}
+	}
+	if r.softDelete != nil {
+		conditions = append(conditions, b.dialect.QuoteIdentifier(r.softDelete.name)+" IS NULL")
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), nil
+}
+
+func (r *Repository[T, ID]) expectRows(result Result, err error) error {
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func assignInsertID(target reflect.Value, id int64) error {
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		target.SetInt(id)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		target.SetUint(uint64(id))
+	default:
+		return fmt.Errorf(repositoryInsertIDMsg, id, target.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,273 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type repositoryUser struct {
+	ID        int64      `db:"id,pk,auto"`
+	Name      string     `db:"name"`
+	Email     string     `db:"email"`
+	DeletedAt *time.Time `db:"deleted_at,softdelete"`
+}
+
+func (repositoryUser) TableName() string {
+	return "users"
+}
+
+type repositoryTag struct {
+	Code  string `db:"code,pk"`
+	Label string
+}
+
+func newRepositoryTestInstance(t *testing.T, name string) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open(sqliteDriver, "file:"+filepath.Join(t.TempDir(), "repository.db"))
+	if err != nil {
+		t.Fatalf("could not open sqlite database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	registerDialect(db, SQLiteDialect)
+	t.Cleanup(func() {
+		unregisterDialect(db)
+		_ = db.Close()
+	})
+
+	schema := `CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL, email TEXT NOT NULL, deleted_at TIMESTAMP);
+CREATE TABLE repository_tags (code TEXT PRIMARY KEY, label TEXT NOT NULL);`
+	if _, err = db.Exec(schema); err != nil {
+		t.Fatalf("could not create schema: %v", err)
+	}
+
+	Register(name, func() *sql.DB { return db })
+	return db
+}
+
+func newUserRepository(t *testing.T, instance string) *Repository[repositoryUser, int64] {
+	t.Helper()
+
+	repository, err := NewRepository[repositoryUser, int64]()
+	if err != nil {
+		t.Fatalf("could not create repository: %v", err)
+	}
+
+	return repository.On(instance)
+}
+
+func TestNewRepository_Metadata(t *testing.T) {
+	users, err := NewRepository[repositoryUser, int64]()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if users.table != "users" || users.pk.name != "id" || users.softDelete == nil || users.softDelete.name != "deleted_at" {
+		t.Fatalf("unexpected user metadata: table=%s pk=%s", users.table, users.pk.name)
+	}
+
+	tags, err := NewRepository[repositoryTag, string](WithTableName("repository_tags"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if tags.table != "repository_tags" || tags.pk.name != "code" || tags.softDelete != nil {
+		t.Fatalf("unexpected tag metadata: table=%s pk=%s", tags.table, tags.pk.name)
+	}
+}
+
+func TestNewRepository_InvalidEntity(t *testing.T) {
+	if _, err := NewRepository[int, int](); err == nil {
+		t.Fatal("expected error for non struct entity, got nil")
+	}
+
+	type withoutKey struct {
+		Name string
+	}
+	if _, err := NewRepository[withoutKey, int](WithTableName("without_keys")); err == nil {
+		t.Fatal("expected error for entity without primary key, got nil")
+	}
+
+	if _, err := NewRepository[repositoryTag, string](); err == nil {
+		t.Fatal("expected error for entity without table name, got nil")
+	}
+}
+
+type repositoryOrder struct {
+	ID    int64  `db:"id,pk,auto"`
+	Group string `db:"group"`
+	Order int    `db:"order"`
+}
+
+func TestRepository_ReservedWords(t *testing.T) {
+	db := newRepositoryTestInstance(t, "repository-reserved")
+	if _, err := db.Exec(`CREATE TABLE "order" (id INTEGER PRIMARY KEY AUTOINCREMENT, "group" TEXT NOT NULL, "order" INTEGER NOT NULL)`); err != nil {
+		t.Fatalf("could not create table: %v", err)
+	}
+	orders, err := NewRepository[repositoryOrder, int64](WithTableName("order"))
+	if err != nil {
+		t.Fatalf("could not create repository: %v", err)
+	}
+	orders = orders.On("repository-reserved")
+	ctx := context.Background()
+
+	order := repositoryOrder{Group: "books", Order: 2}
+	if err = orders.Insert(ctx, &order); err != nil {
+		t.Fatalf("expected insert to succeed, got %v", err)
+	}
+	order.Order = 3
+	if err = orders.Update(ctx, &order); err != nil {
+		t.Fatalf("expected update to succeed, got %v", err)
+	}
+
+	found, err := orders.FindAll(ctx, Filter{"group": "books"})
+	if err != nil || len(found) != 1 || found[0].Order != 3 {
+		t.Fatalf("expected the updated order, got %+v, %v", found, err)
+	}
+	if err = orders.Delete(ctx, order.ID); err != nil {
+		t.Fatalf("expected delete to succeed, got %v", err)
+	}
+}
+
+func TestRepository_CRUD(t *testing.T) {
+	newRepositoryTestInstance(t, "repository-crud")
+	users := newUserRepository(t, "repository-crud")
+	ctx := context.Background()
+
+	alice := repositoryUser{Name: "Alice", Email: "alice@example.com"}
+	if err := users.Insert(ctx, &alice); err != nil {
+		t.Fatalf("expected insert to succeed, got %v", err)
+	}
+	if alice.ID == 0 {
+		t.Fatal("expected generated id to be assigned")
+	}
+	bob := repositoryUser{Name: "Bob", Email: "bob@example.com"}
+	if err := users.Insert(ctx, &bob); err != nil {
+		t.Fatalf("expected insert to succeed, got %v", err)
+	}
+
+	found, err := users.FindByID(ctx, alice.ID)
+	if err != nil || found.Name != "Alice" {
+		t.Fatalf("expected Alice, got %+v, %v", found, err)
+	}
+
+	alice.Email = "alice@example.org"
+	if err = users.Update(ctx, &alice); err != nil {
+		t.Fatalf("expected update to succeed, got %v", err)
+	}
+	found, _ = users.FindByID(ctx, alice.ID)
+	if found.Email != "alice@example.org" {
+		t.Fatalf("expected updated email, got %s", found.Email)
+	}
+
+	all, err := users.FindAll(ctx, nil)
+	if err != nil || len(all) != 2 || all[0].Name != "Alice" || all[1].Name != "Bob" {
+		t.Fatalf("expected Alice and Bob, got %+v, %v", all, err)
+	}
+
+	filtered, err := users.FindAll(ctx, Filter{"name": "Bob"})
+	if err != nil || len(filtered) != 1 || filtered[0].ID != bob.ID {
+		t.Fatalf("expected only Bob, got %+v, %v", filtered, err)
+	}
+
+	if count, err := users.Count(ctx, nil); err != nil || count != 2 {
+		t.Fatalf("expected count 2, got %d, %v", count, err)
+	}
+	if exists, err := users.Exists(ctx, bob.ID); err != nil || !exists {
+		t.Fatalf("expected Bob to exist, got %t, %v", exists, err)
+	}
+}
+
+func TestRepository_SoftDelete(t *testing.T) {
+	db := newRepositoryTestInstance(t, "repository-soft-delete")
+	users := newUserRepository(t, "repository-soft-delete")
+	ctx := context.Background()
+
+	alice := repositoryUser{Name: "Alice", Email: "alice@example.com"}
+	if err := users.Insert(ctx, &alice); err != nil {
+		t.Fatalf("expected insert to succeed, got %v", err)
+	}
+
+	if err := users.Delete(ctx, alice.ID); err != nil {
+		t.Fatalf("expected delete to succeed, got %v", err)
+	}
+
+	if _, err := users.FindByID(ctx, alice.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows after soft delete, got %v", err)
+	}
+	if exists, _ := users.Exists(ctx, alice.ID); exists {
+		t.Fatal("expected soft deleted row not to exist")
+	}
+	if count, _ := users.Count(ctx, nil); count != 0 {
+		t.Fatalf("expected count 0, got %d", count)
+	}
+	if err := users.Delete(ctx, alice.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows deleting twice, got %v", err)
+	}
+	if err := users.Update(ctx, &alice); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows updating deleted row, got %v", err)
+	}
+
+	var rows int
+	if err := db.QueryRow("SELECT COUNT(*) FROM users WHERE deleted_at IS NOT NULL").Scan(&rows); err != nil || rows != 1 {
+		t.Fatalf("expected row to be kept with deleted_at, got %d, %v", rows, err)
+	}
+}
+
+func TestRepository_HardDelete(t *testing.T) {
+	newRepositoryTestInstance(t, "repository-hard-delete")
+	tags, err := NewRepository[repositoryTag, string](WithTableName("repository_tags"))
+	if err != nil {
+		t.Fatalf("could not create repository: %v", err)
+	}
+	tags = tags.On("repository-hard-delete")
+	ctx := context.Background()
+
+	if err = tags.Insert(ctx, &repositoryTag{Code: "go", Label: "Golang"}); err != nil {
+		t.Fatalf("expected insert to succeed, got %v", err)
+	}
+	if err = tags.Delete(ctx, "go"); err != nil {
+		t.Fatalf("expected delete to succeed, got %v", err)
+	}
+	if count, _ := tags.Count(ctx, nil); count != 0 {
+		t.Fatalf("expected row to be removed, got count %d", count)
+	}
+}
+
+func TestRepository_InvalidFilter(t *testing.T) {
+	newRepositoryTestInstance(t, "repository-filter")
+	users := newUserRepository(t, "repository-filter")
+
+	_, err := users.FindAll(context.Background(), Filter{"name; DROP TABLE users": "x"})
+	if err == nil {
+		t.Fatal("expected error for unknown filter column, got nil")
+	}
+}
+
+func TestRepository_TransactionAndPlaceholders(t *testing.T) {
+	db, drv := newFakeDB(t, func(query string, _ []driver.Value) fakeResponse {
+		return fakeResponse{rowsAffected: 1}
+	})
+	Register("repository-tx", func() *sql.DB { return db })
+	tags, _ := NewRepository[repositoryTag, string](WithTableName("repository_tags"))
+	tags = tags.On("repository-tx")
+
+	err := WithTransactionInInstance(context.Background(), db, func(ctx context.Context) error {
+		return tags.Update(ctx, &repositoryTag{Code: "go", Label: "Golang"})
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	events := drv.Events()
+	if len(events) != 3 || events[0] != "BEGIN" || events[2] != "COMMIT" {
+		t.Fatalf("expected update inside transaction, got %v", events)
+	}
+	if !strings.Contains(events[1], `UPDATE "repository_tags" SET "label" = $1 WHERE "code" = $2`) {
+		t.Fatalf("expected postgres placeholders, got %s", events[1])
+	}
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+)
+
+const defaultReturningColumn string = "id"
+
+var returningClause = regexp.MustCompile(`(?i)\bRETURNING\b`)
+
+type Result struct {
+	RowsAffected int64
+	LastInsertID int64
+}
+
+func (s *Statement) ExecuteWithResult() (Result, error) {
+	return s.ExecuteWithResultInInstance(Instance(s.instance))
+}
+
+func (s *Statement) ExecuteWithResultInInstance(instance *sql.DB) (Result, error) {
+	sqlResult, err := s.execInInstance(instance)
+	if err != nil {
+		return Result{}, err
+	}
+
+	rowsAffected, err := sqlResult.RowsAffected()
+	if err != nil {
+		return Result{}, err
+	}
+
+	// lib/pq does not support LastInsertId, use InsertReturning for PostgreSQL.
+	lastInsertID, _ := sqlResult.LastInsertId()
+
+	return Result{RowsAffected: rowsAffected, LastInsertID: lastInsertID}, nil
+}
+
+func InsertReturning[T any](s *Statement, columns ...string) (T, error) {
+	return InsertReturningInInstance[T](s, Instance(s.instance), columns...)
+}
+
+func InsertReturningInInstance[T any](s *Statement, instance *sql.DB, columns ...string) (T, error) {
+	return QueryOneInInstance[T](s.withReturning(columns), instance)
+}
+
+func (s *Statement) withReturning(columns []string) *Statement {
+	if returningClause.MatchString(s.query) {
+		return s
+	}
+
+	if len(columns) == 0 {
+		columns = []string{defaultReturningColumn}
+	}
+
+	query := strings.TrimRight(strings.TrimSpace(s.query), ";")
+	return &Statement{ctx: s.ctx, query: query + " RETURNING " + strings.Join(columns, ", "), args: s.args, instance: s.instance}
+}
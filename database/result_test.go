@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestExecuteWithResultInInstance(t *testing.T) {
+	db, _ := newFakeDB(t, func(string, []driver.Value) fakeResponse {
+		return fakeResponse{rowsAffected: 3, lastInsertID: 10}
+	})
+
+	result, err := NewStatement(context.Background(), "UPDATE users SET active = $1", true).ExecuteWithResultInInstance(db)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.RowsAffected != 3 {
+		t.Fatalf("expected RowsAffected=3, got %d", result.RowsAffected)
+	}
+	if result.LastInsertID != 10 {
+		t.Fatalf("expected LastInsertID=10, got %d", result.LastInsertID)
+	}
+}
+
+func TestExecuteWithResultInInstance_NilInstance(t *testing.T) {
+	_, err := NewStatement(context.Background(), "UPDATE users SET active = true").ExecuteWithResultInInstance(nil)
+	if err == nil || err.Error() != dbNotInitializedErrorMsg {
+		t.Fatalf("expected '%s', got %v", dbNotInitializedErrorMsg, err)
+	}
+}
+
+func TestStatement_WithReturning(t *testing.T) {
+	cases := []struct {
+		query    string
+		columns  []string
+		expected string
+	}{
+		{"INSERT INTO users (name) VALUES ($1)", nil, "INSERT INTO users (name) VALUES ($1) RETURNING id"},
+		{"INSERT INTO users (name) VALUES ($1);", []string{"id", "created_at"}, "INSERT INTO users (name) VALUES ($1) RETURNING id, created_at"},
+		{"INSERT INTO users (name) VALUES ($1) returning uuid", []string{"id"}, "INSERT INTO users (name) VALUES ($1) returning uuid"},
+	}
+
+	for _, c := range cases {
+		stmt := NewStatement(context.Background(), c.query, "Alice").withReturning(c.columns)
+		if stmt.query != c.expected {
+			t.Fatalf("expected:\n%s\ngot:\n%s", c.expected, stmt.query)
+		}
+		if len(stmt.args) != 1 {
+			t.Fatalf("expected args to be preserved, got %v", stmt.args)
+		}
+	}
+}
+
+func TestInsertReturningInInstance(t *testing.T) {
+	var executed string
+	db, _ := newFakeDB(t, func(query string, _ []driver.Value) fakeResponse {
+		executed = query
+		return fakeResponse{columns: []string{"id"}, rows: [][]driver.Value{{int64(7)}}}
+	})
+
+	id, err := InsertReturningInInstance[int64](NewStatement(context.Background(), "INSERT INTO users (name) VALUES ($1)", "Alice"), db)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if id != 7 {
+		t.Fatalf("expected id=7, got %d", id)
+	}
+	if executed != "INSERT INTO users (name) VALUES ($1) RETURNING id" {
+		t.Fatalf("unexpected query executed: %s", executed)
+	}
+}
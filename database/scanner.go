@@ -0,0 +1,215 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	dbTagName                  string = "db"
+	columnWithoutFieldErrorMsg string = "column %s has no matching field in %s"
+	scalarColumnsErrorMsg      string = "expected 1 column to scan into %s, got %d"
+)
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	fieldCache  sync.Map
+)
+
+type fieldPath []int
+
+type structField struct {
+	name    string
+	path    fieldPath
+	options []string
+}
+
+// structInfo keeps the mapped fields in declaration order, as the repository
+// needs a stable column order, and indexed by column for the scanner.
+type structInfo struct {
+	fields []structField
+	byName map[string]fieldPath
+}
+
+func scanRows[T any](rows *sql.Rows) ([]T, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]T, 0)
+	for rows.Next() {
+		var item T
+		if err := scanRow(rows, columns, &item); err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func scanRow(rows *sql.Rows, columns []string, dest any) error {
+	targets, err := scanTargets(reflect.ValueOf(dest).Elem(), columns)
+	if err != nil {
+		return err
+	}
+
+	return rows.Scan(targets...)
+}
+
+func scanTargets(value reflect.Value, columns []string) ([]any, error) {
+	if !isStructDestination(value.Type()) {
+		if len(columns) != 1 {
+			return nil, fmt.Errorf(scalarColumnsErrorMsg, value.Type(), len(columns))
+		}
+		return []any{value.Addr().Interface()}, nil
+	}
+
+	fields := structFields(value.Type())
+	targets := make([]any, len(columns))
+	for i, column := range columns {
+		path, ok := fields[strings.ToLower(column)]
+		if !ok {
+			return nil, fmt.Errorf(columnWithoutFieldErrorMsg, column, value.Type())
+		}
+		targets[i] = fieldByPath(value, path).Addr().Interface()
+	}
+
+	return targets, nil
+}
+
+// isStructDestination reports whether rows are mapped into the fields of t.
+// Structs without exported or embedded fields, such as time.Time, are
+// scanned as a single value, as are sql.Scanner implementations.
+func isStructDestination(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || reflect.PointerTo(t).Implements(scannerType) {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() || field.Anonymous {
+			return true
+		}
+	}
+
+	return false
+}
+
+func structFields(t reflect.Type) map[string]fieldPath {
+	return describeStruct(t).byName
+}
+
+func describeStruct(t reflect.Type) *structInfo {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.(*structInfo)
+	}
+
+	info := &structInfo{byName: make(map[string]fieldPath)}
+	collectFields(t, nil, info)
+	fieldCache.Store(t, info)
+
+	return info
+}
+
+func collectFields(t reflect.Type, parent fieldPath, info *structInfo) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get(dbTagName)
+		if tag == "-" {
+			continue
+		}
+
+		path := append(append(fieldPath{}, parent...), i)
+
+		if field.Anonymous && tag == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				if !field.IsExported() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if isStructDestination(embedded) {
+				collectFields(embedded, path, info)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name := columnName(field)
+		if _, exists := info.byName[name]; !exists {
+			info.byName[name] = path
+			info.fields = append(info.fields, structField{name: name, path: path, options: tagOptions(tag)})
+		}
+	}
+}
+
+func columnName(field reflect.StructField) string {
+	if tag, _, _ := strings.Cut(field.Tag.Get(dbTagName), ","); tag != "" {
+		return strings.ToLower(tag)
+	}
+
+	return toSnakeCase(field.Name)
+}
+
+func tagOptions(tag string) []string {
+	_, options, found := strings.Cut(tag, ",")
+	if !found {
+		return nil
+	}
+
+	return strings.Split(options, ",")
+}
+
+func (f structField) hasOption(option string) bool {
+	for _, o := range f.options {
+		if strings.TrimSpace(o) == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+func fieldByPath(value reflect.Value, path fieldPath) reflect.Value {
+	for i, index := range path {
+		if i > 0 && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(index)
+	}
+
+	return value
+}
+
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				builder.WriteRune('_')
+			}
+			builder.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
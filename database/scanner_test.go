@@ -0,0 +1,129 @@
+package database
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type scannerBase struct {
+	ID        int64  `db:"id"`
+	CreatedBy string `db:"created_by"`
+}
+
+type ScannerAudit struct {
+	UpdatedBy string
+}
+
+type scannerUser struct {
+	scannerBase
+	*ScannerAudit
+	Name     string         `db:"name"`
+	Nickname *string        `db:"nickname"`
+	Email    sql.NullString `db:"email"`
+	Ignored  string         `db:"-"`
+	internal string
+}
+
+func TestToSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"ID":         "id",
+		"Name":       "name",
+		"UserID":     "user_id",
+		"CreatedAt":  "created_at",
+		"HTTPServer": "http_server",
+	}
+
+	for input, expected := range cases {
+		if got := toSnakeCase(input); got != expected {
+			t.Fatalf("expected %s -> %s, got %s", input, expected, got)
+		}
+	}
+}
+
+func TestStructFields_TagsAndEmbedded(t *testing.T) {
+	fields := structFields(reflect.TypeOf(scannerUser{}))
+
+	for _, column := range []string{"id", "created_by", "updated_by", "name", "nickname", "email"} {
+		if _, ok := fields[column]; !ok {
+			t.Fatalf("expected column %s to be mapped", column)
+		}
+	}
+	if _, ok := fields["ignored"]; ok {
+		t.Fatal("expected column tagged with '-' to be skipped")
+	}
+	if _, ok := fields["internal"]; ok {
+		t.Fatal("expected unexported field to be skipped")
+	}
+}
+
+func TestScanTargets_UnknownColumn(t *testing.T) {
+	var user scannerUser
+
+	_, err := scanTargets(reflect.ValueOf(&user).Elem(), []string{"id", "unknown"})
+	if err == nil {
+		t.Fatal("expected error for unknown column, got nil")
+	}
+}
+
+func TestScanTargets_AllocatesEmbeddedPointer(t *testing.T) {
+	var user scannerUser
+
+	targets, err := scanTargets(reflect.ValueOf(&user).Elem(), []string{"updated_by"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(targets) != 1 {
+		t.Fatalf("expected 1 target, got %d", len(targets))
+	}
+	if user.ScannerAudit == nil {
+		t.Fatal("expected embedded pointer to be allocated")
+	}
+}
+
+func TestScanTargets_ScalarDestination(t *testing.T) {
+	var count int64
+
+	if _, err := scanTargets(reflect.ValueOf(&count).Elem(), []string{"count"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := scanTargets(reflect.ValueOf(&count).Elem(), []string{"a", "b"}); err == nil {
+		t.Fatal("expected error for multiple columns on scalar destination, got nil")
+	}
+}
+
+func TestIsStructDestination(t *testing.T) {
+	cases := map[reflect.Type]bool{
+		reflect.TypeFor[scannerUser]():      true,
+		reflect.TypeFor[time.Time]():        false,
+		reflect.TypeFor[sql.NullString]():   false,
+		reflect.TypeFor[struct{ id int }](): false,
+		reflect.TypeFor[int64]():            false,
+	}
+
+	for typ, expected := range cases {
+		if got := isStructDestination(typ); got != expected {
+			t.Fatalf("%s: expected %t, got %t", typ, expected, got)
+		}
+	}
+}
+
+func TestDescribeStruct_OrderAndOptions(t *testing.T) {
+	info := describeStruct(reflect.TypeOf(repositoryUser{}))
+
+	names := make([]string, len(info.fields))
+	for i, field := range info.fields {
+		names[i] = field.name
+	}
+	if strings.Join(names, ",") != "id,name,email,deleted_at" {
+		t.Fatalf("expected fields in declaration order, got %v", names)
+	}
+	if !info.fields[0].hasOption("pk") || !info.fields[0].hasOption("auto") {
+		t.Fatalf("expected pk and auto options on id, got %v", info.fields[0].options)
+	}
+	if info.fields[1].hasOption("pk") {
+		t.Fatal("expected name not to be a primary key")
+	}
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/sdkopen/sdkopen-go/common/env"
+	"github.com/sdkopen/sdkopen-go/logging"
+
+	_ "modernc.org/sqlite"
+)
+
+const (
+	sqliteDriver        string = "sqlite"
+	sqliteDefaultPragma string = "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+)
+
+type SQLiteConnector struct {
+	path string
+	connectorConfig
+}
+
+func NewDefaultSQLiteConnector(opts ...ConnectorOption) *SQLiteConnector {
+	return newSQLiteConnector(env.DefaultSqlDB(), opts)
+}
+
+func NewPrefixedSQLiteConnector(prefix string, opts ...ConnectorOption) *SQLiteConnector {
+	cfg, err := env.LoadSqlDB(prefix)
+	if err != nil {
+		logging.Fatal("%s", err.Error())
+	}
+
+	return newSQLiteConnector(cfg, opts)
+}
+
+func newSQLiteConnector(cfg env.SqlDB, opts []ConnectorOption) *SQLiteConnector {
+	connector := &SQLiteConnector{
+		path:            cfg.Name,
+		connectorConfig: newConnectorConfig(cfg, opts),
+	}
+
+	// Each connection to an in-memory path gets a database of its own, so
+	// the pool keeps a single connection open for the whole process.
+	if isSQLiteMemory(connector.path) {
+		connector.pool = PoolConfig{MaxOpenConns: 1, MaxIdleConns: 1}
+	}
+
+	return connector
+}
+
+func (c *SQLiteConnector) Connect() *sql.DB {
+	return c.open(connectTarget{
+		driver:  sqliteDriver,
+		dialect: SQLiteDialect,
+		dsn:     c.getDSN(),
+		address: c.path,
+	})
+}
+
+// getDSN turns on foreign keys and a busy timeout unless the path already
+// carries its own query parameters.
+func (c *SQLiteConnector) getDSN() string {
+	if strings.Contains(c.path, "?") {
+		return c.path
+	}
+
+	return "file:" + c.path + "?" + sqliteDefaultPragma
+}
+
+func isSQLiteMemory(path string) bool {
+	return path == ":memory:" || strings.HasPrefix(path, "file::memory:") || strings.Contains(path, "mode=memory")
+}
+
+func SQLite() *sql.DB {
+	return NewDefaultSQLiteConnector().Connect()
+}
+
+func SQLiteWithPrefix(prefix string) func() *sql.DB {
+	return func() *sql.DB {
+		return NewPrefixedSQLiteConnector(prefix).Connect()
+	}
+}
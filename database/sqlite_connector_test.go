@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/common/env"
+)
+
+func TestSQLiteConnector_GetDSN(t *testing.T) {
+	connector := newSQLiteConnector(env.SqlDB{Name: "/data/app.db"}, nil)
+	if dsn := connector.getDSN(); dsn != "file:/data/app.db?"+sqliteDefaultPragma {
+		t.Fatalf("unexpected dsn: %s", dsn)
+	}
+
+	connector = newSQLiteConnector(env.SqlDB{Name: "file::memory:?cache=shared"}, nil)
+	if dsn := connector.getDSN(); dsn != "file::memory:?cache=shared" {
+		t.Fatalf("expected dsn with custom parameters to be kept, got %s", dsn)
+	}
+}
+
+func TestSQLiteConnector_ConnectAndMigrate(t *testing.T) {
+	dir := t.TempDir()
+	migrations := filepath.Join(dir, "migrations")
+	if err := os.Mkdir(migrations, 0o755); err != nil {
+		t.Fatalf("could not create migrations dir: %v", err)
+	}
+	up := "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);"
+	if err := os.WriteFile(filepath.Join(migrations, "1_create_users.up.sql"), []byte(up), 0o644); err != nil {
+		t.Fatalf("could not write migration: %v", err)
+	}
+
+	connector := newSQLiteConnector(env.SqlDB{Name: filepath.Join(dir, "app.db")}, []ConnectorOption{
+		WithAutoMigration(true),
+		WithMigrations(WithMigrationPath(migrations)),
+		WithMaxOpenConns(1),
+	})
+	db := connector.Connect()
+	t.Cleanup(func() {
+		unregisterDialect(db)
+		_ = db.Close()
+	})
+
+	if dialectOf(db) != SQLiteDialect {
+		t.Fatalf("expected sqlite dialect, got %s", dialectOf(db).Name())
+	}
+
+	if err := NewStatement(context.Background(), "INSERT INTO users (name) VALUES (?)", "Alice").ExecuteInInstance(db); err != nil {
+		t.Fatalf("expected insert to succeed after migration, got %v", err)
+	}
+	name, err := QueryScalarInInstance[string](NewStatement(context.Background(), "SELECT name FROM users WHERE id = ?", 1), db)
+	if err != nil || name != "Alice" {
+		t.Fatalf("expected Alice, got %q, %v", name, err)
+	}
+}
+
+func TestSQLiteConnector_InMemory(t *testing.T) {
+	for _, path := range []string{":memory:", "file::memory:?cache=shared", "file:test?mode=memory"} {
+		connector := newSQLiteConnector(env.SqlDB{Name: path, MaxOpenConns: 10, MaxIdleConns: 5, ConnMaxIdleTime: time.Minute}, nil)
+		if connector.pool != (PoolConfig{MaxOpenConns: 1, MaxIdleConns: 1}) {
+			t.Fatalf("expected a single long lived connection for %s, got %+v", path, connector.pool)
+		}
+	}
+
+	dir := t.TempDir()
+	up := "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);"
+	if err := os.WriteFile(filepath.Join(dir, "1_create_users.up.sql"), []byte(up), 0o644); err != nil {
+		t.Fatalf("could not write migration: %v", err)
+	}
+
+	db := newSQLiteConnector(env.SqlDB{Name: ":memory:", MaxOpenConns: 10, MaxIdleConns: 5}, []ConnectorOption{
+		WithAutoMigration(true),
+		WithMigrations(WithMigrationPath(dir)),
+	}).Connect()
+	t.Cleanup(func() {
+		unregisterDialect(db)
+		_ = db.Close()
+	})
+
+	ctx := context.Background()
+	for range 5 {
+		if err := NewStatement(ctx, "INSERT INTO users (name) VALUES (?)", "Alice").ExecuteInInstance(db); err != nil {
+			t.Fatalf("expected migrated table on every query, got %v", err)
+		}
+	}
+}
@@ -10,46 +10,80 @@ import (
 )
 
 const (
-	sqlTxContext         string = "SqlTxContext"
 	closerErrorMsg       string = "Could not close statement: %v"
 	queryIsEmptyErrorMsg string = "query is empty"
 )
 
 type Statement struct {
-	ctx   context.Context
-	query string
-	args  []any
+	ctx      context.Context
+	query    string
+	args     []any
+	instance string
 }
 
 func NewStatement(ctx context.Context, query string, params ...any) *Statement {
-	return &Statement{ctx, query, params}
+	return &Statement{ctx: ctx, query: query, args: params}
+}
+
+func (s *Statement) On(instance string) *Statement {
+	s.instance = instance
+	return s
 }
 
 func (s *Statement) Execute() error {
-	return s.ExecuteInInstance(dbInstance)
+	return s.ExecuteInInstance(Instance(s.instance))
 }
 
 func (s *Statement) ExecuteInInstance(instance *sql.DB) error {
+	_, err := s.execInInstance(instance)
+	return err
+}
+
+func (s *Statement) execInInstance(instance *sql.DB) (sql.Result, error) {
 	if err := s.validate(instance); err != nil {
-		return err
+		return nil, err
 	}
 
-	stmt, err := s.createStatement(instance)
-	if err != nil {
+	var result sql.Result
+	err := s.instrument(QueryExec, func(s *Statement) error {
+		stmt, err := s.createStatement(instance)
+		if err != nil {
+			return err
+		}
+		defer closer(stmt)
+
+		result, err = stmt.ExecContext(s.ctx, s.args...)
 		return err
-	}
-	defer closer(stmt)
+	})
+
+	return result, err
+}
 
-	if _, err = stmt.Exec(s.args...); err != nil {
+func (s *Statement) queryInInstance(instance *sql.DB, scan func(rows *sql.Rows) error) error {
+	if err := s.validate(instance); err != nil {
 		return err
 	}
 
-	return nil
+	return s.instrument(QueryQuery, func(s *Statement) error {
+		stmt, err := s.createStatement(instance)
+		if err != nil {
+			return err
+		}
+		defer closer(stmt)
+
+		rows, err := stmt.QueryContext(s.ctx, s.args...)
+		if err != nil {
+			return err
+		}
+		defer closer(rows)
+
+		return scan(rows)
+	})
 }
 
 func (s *Statement) createStatement(instance *sql.DB) (*sql.Stmt, error) {
-	if tx := s.ctx.Value(sqlTxContext); tx != nil {
-		return tx.(*sql.Tx).PrepareContext(s.ctx, s.query)
+	if current := transactionFor(s.ctx, instance); current != nil {
+		return current.tx.PrepareContext(s.ctx, s.query)
 	}
 
 	return instance.PrepareContext(s.ctx, s.query)
@@ -91,3 +91,18 @@ func TestStatement_ExecuteInInstance_EmptyQuery(t *testing.T) {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+type statementContextKey struct{}
+
+func TestStatement_ExecuteInInstance_PassesContext(t *testing.T) {
+	db, drv := newFakeDB(t, nil)
+	ctx := context.WithValue(context.Background(), statementContextKey{}, "request-42")
+
+	if err := NewStatement(ctx, "UPDATE users SET active = $1", true).ExecuteInInstance(db); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := drv.ExecContext(); got == nil || got.Value(statementContextKey{}) != "request-42" {
+		t.Fatal("expected statement context to reach the driver")
+	}
+}
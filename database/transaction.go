@@ -0,0 +1,221 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/common/retry"
+	"github.com/sdkopen/sdkopen-go/logging"
+)
+
+type contextKey string
+
+const (
+	sqlTxContext contextKey = "SqlTxContext"
+
+	savepointNameFormat       string = "sdkopen_savepoint_%d"
+	txRetryOperation          string = "transaction"
+	txBeginErrorMsg           string = "could not begin transaction: %w"
+	txRollbackErrorMsg        string = "Could not rollback transaction: %v"
+	txPanicRollbackMsg        string = "Rolling back transaction after panic: %v"
+	savepointRollbackErrorMsg string = "Could not rollback to savepoint %s: %v"
+)
+
+// transaction is the transaction of one instance carried by the context.
+// parent is the transaction that was in the context before it, possibly of
+// another instance, so transactions on several instances can be nested.
+type transaction struct {
+	instance *sql.DB
+	tx       *sql.Tx
+	depth    int
+	parent   *transaction
+}
+
+type TxOption func(*txConfig)
+
+type txConfig struct {
+	options sql.TxOptions
+	retry   retry.Config
+}
+
+// DefaultTxRetryConfig retries a transaction up to 3 times, starting with a
+// short backoff, as conflicting transactions usually clear quickly.
+func DefaultTxRetryConfig() retry.Config {
+	return retry.Config{
+		MaxAttempts:     3,
+		InitialInterval: 20 * time.Millisecond,
+		MaxInterval:     time.Second,
+		Multiplier:      2,
+		Jitter:          0.2,
+	}
+}
+
+func WithIsolationLevel(level sql.IsolationLevel) TxOption {
+	return func(c *txConfig) {
+		c.options.Isolation = level
+	}
+}
+
+func WithReadOnly() TxOption {
+	return func(c *txConfig) {
+		c.options.ReadOnly = true
+	}
+}
+
+// WithTxRetry replaces the backoff used when the transaction fails with a
+// serialization failure or a deadlock. Zero MaxAttempts, InitialInterval,
+// MaxInterval and Multiplier take the values of DefaultTxRetryConfig.
+// MaxAttempts 1 disables the retry.
+func WithTxRetry(cfg retry.Config) TxOption {
+	return func(c *txConfig) {
+		defaults := DefaultTxRetryConfig()
+		if cfg.MaxAttempts == 0 {
+			cfg.MaxAttempts = defaults.MaxAttempts
+		}
+		if cfg.InitialInterval <= 0 {
+			cfg.InitialInterval = defaults.InitialInterval
+		}
+		if cfg.MaxInterval <= 0 {
+			cfg.MaxInterval = defaults.MaxInterval
+		}
+		if cfg.Multiplier <= 0 {
+			cfg.Multiplier = defaults.Multiplier
+		}
+
+		c.retry = cfg
+	}
+}
+
+// WithoutTxRetry runs the transaction once, returning serialization failures
+// and deadlocks to the caller.
+func WithoutTxRetry() TxOption {
+	return WithTxRetry(retry.Config{MaxAttempts: 1})
+}
+
+func WithTransaction(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error {
+	return WithTransactionInInstance(ctx, dbInstance, fn, opts...)
+}
+
+// WithTransactionInInstance runs fn in a transaction, retrying the whole
+// function on serialization failures and deadlocks. Nested calls run in a
+// savepoint of the outer transaction and ignore opts.
+func WithTransactionInInstance(ctx context.Context, instance *sql.DB, fn func(ctx context.Context) error, opts ...TxOption) error {
+	if current := transactionFor(ctx, instance); current != nil {
+		return withSavepoint(ctx, current, fn)
+	}
+
+	if instance == nil {
+		return errors.New(dbNotInitializedErrorMsg)
+	}
+
+	cfg := txConfig{retry: DefaultTxRetryConfig()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return retry.Do(ctx, txRetryOperation, cfg.retry, func() error {
+		tx, err := instance.BeginTx(ctx, &cfg.options)
+		if err != nil {
+			return retry.Permanent(fmt.Errorf(txBeginErrorMsg, err))
+		}
+
+		err = runInTransaction(ctx, &transaction{instance: instance, tx: tx, parent: transactionFromContext(ctx)}, fn)
+		if err != nil && !IsSerializationFailure(err) && !IsDeadlock(err) {
+			return retry.Permanent(err)
+		}
+
+		return err
+	})
+}
+
+func runInTransaction(ctx context.Context, current *transaction, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logging.Error(txPanicRollbackMsg, r)
+			rollback(current.tx)
+			panic(r)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, sqlTxContext, current)); err != nil {
+		rollback(current.tx)
+		return err
+	}
+
+	return current.tx.Commit()
+}
+
+func withSavepoint(ctx context.Context, parent *transaction, fn func(ctx context.Context) error) (err error) {
+	nested := &transaction{instance: parent.instance, tx: parent.tx, depth: parent.depth + 1, parent: transactionFromContext(ctx)}
+	name := fmt.Sprintf(savepointNameFormat, nested.depth)
+
+	if _, err = nested.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			rollbackToSavepoint(ctx, nested.tx, name)
+			panic(r)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, sqlTxContext, nested)); err != nil {
+		rollbackToSavepoint(ctx, nested.tx, name)
+		return err
+	}
+
+	_, err = nested.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
+// InTransaction reports whether ctx carries a transaction started by
+// WithTransaction on the default instance.
+func InTransaction(ctx context.Context) bool {
+	return InTransactionInInstance(ctx, dbInstance)
+}
+
+func InTransactionInInstance(ctx context.Context, instance *sql.DB) bool {
+	return transactionFor(ctx, instance) != nil
+}
+
+// transactionFromContext returns the innermost transaction in ctx, of any
+// instance.
+func transactionFromContext(ctx context.Context) *transaction {
+	if ctx == nil {
+		return nil
+	}
+
+	current, _ := ctx.Value(sqlTxContext).(*transaction)
+	return current
+}
+
+// transactionFor returns the innermost transaction of instance in ctx.
+func transactionFor(ctx context.Context, instance *sql.DB) *transaction {
+	if instance == nil {
+		return nil
+	}
+
+	for current := transactionFromContext(ctx); current != nil; current = current.parent {
+		if current.instance == instance {
+			return current
+		}
+	}
+
+	return nil
+}
+
+func rollback(tx *sql.Tx) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		logging.Error(txRollbackErrorMsg, err)
+	}
+}
+
+func rollbackToSavepoint(ctx context.Context, tx *sql.Tx, name string) {
+	if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); err != nil {
+		logging.Error(savepointRollbackErrorMsg, name, err)
+	}
+}
@@ -0,0 +1,277 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sdkopen/sdkopen-go/common/retry"
+)
+
+func TestWithTransactionInInstance_Commit(t *testing.T) {
+	db, drv := newFakeDB(t, nil)
+
+	err := WithTransactionInInstance(context.Background(), db, func(ctx context.Context) error {
+		if transactionFromContext(ctx) == nil {
+			t.Fatal("expected transaction in context")
+		}
+		return NewStatement(ctx, "INSERT INTO users (name) VALUES ($1)", "Alice").ExecuteInInstance(db)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{"BEGIN", "INSERT INTO users (name) VALUES ($1)", "COMMIT"}
+	if events := drv.Events(); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+}
+
+func TestWithTransactionInInstance_RollbackOnError(t *testing.T) {
+	db, drv := newFakeDB(t, nil)
+	expectedErr := errors.New("boom")
+
+	err := WithTransactionInInstance(context.Background(), db, func(ctx context.Context) error {
+		return expectedErr
+	})
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("expected %v, got %v", expectedErr, err)
+	}
+
+	expected := []string{"BEGIN", "ROLLBACK"}
+	if events := drv.Events(); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+}
+
+func TestWithTransactionInInstance_RollbackOnPanic(t *testing.T) {
+	db, drv := newFakeDB(t, nil)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected panic to be propagated")
+		}
+		expected := []string{"BEGIN", "ROLLBACK"}
+		if events := drv.Events(); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("expected events %v, got %v", expected, events)
+		}
+	}()
+
+	_ = WithTransactionInInstance(context.Background(), db, func(ctx context.Context) error {
+		panic("boom")
+	})
+}
+
+func TestWithTransactionInInstance_NestedUsesSavepoints(t *testing.T) {
+	db, drv := newFakeDB(t, nil)
+
+	err := WithTransactionInInstance(context.Background(), db, func(ctx context.Context) error {
+		if err := WithTransactionInInstance(ctx, db, func(ctx context.Context) error {
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		_ = WithTransactionInInstance(ctx, db, func(ctx context.Context) error {
+			return errors.New("nested failure")
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"BEGIN",
+		"SAVEPOINT sdkopen_savepoint_1",
+		"RELEASE SAVEPOINT sdkopen_savepoint_1",
+		"SAVEPOINT sdkopen_savepoint_1",
+		"ROLLBACK TO SAVEPOINT sdkopen_savepoint_1",
+		"COMMIT",
+	}
+	if events := drv.Events(); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+}
+
+func TestInTransactionInInstance(t *testing.T) {
+	db, _ := newFakeDB(t, nil)
+	other, _ := newFakeDB(t, nil)
+
+	if InTransactionInInstance(context.Background(), db) {
+		t.Fatal("expected no transaction outside WithTransaction")
+	}
+
+	_ = WithTransactionInInstance(context.Background(), db, func(ctx context.Context) error {
+		if !InTransactionInInstance(ctx, db) {
+			t.Fatal("expected transaction in context")
+		}
+		if InTransactionInInstance(ctx, other) {
+			t.Fatal("expected transaction to belong to its own instance only")
+		}
+		return nil
+	})
+}
+
+func TestWithTransactionInInstance_NilInstance(t *testing.T) {
+	err := WithTransactionInInstance(context.Background(), nil, func(ctx context.Context) error {
+		return nil
+	})
+	if err == nil || err.Error() != dbNotInitializedErrorMsg {
+		t.Fatalf("expected '%s', got %v", dbNotInitializedErrorMsg, err)
+	}
+}
+
+func TestWithTransactionInInstance_Options(t *testing.T) {
+	db, drv := newFakeDB(t, nil)
+
+	err := WithTransactionInInstance(context.Background(), db, func(ctx context.Context) error {
+		return nil
+	}, WithIsolationLevel(sql.LevelSerializable), WithReadOnly())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{"BEGIN SERIALIZABLE READ ONLY", "COMMIT"}
+	if events := drv.Events(); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+}
+
+func TestWithTransactionInInstance_RetriesSerializationFailure(t *testing.T) {
+	db, drv := newFakeDB(t, nil)
+
+	calls := 0
+	err := WithTransactionInInstance(context.Background(), db, func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			return &pq.Error{Code: "40001"}
+		}
+		if calls == 2 {
+			return fmt.Errorf("update stock: %w", &pq.Error{Code: "40P01"})
+		}
+		return nil
+	}, WithTxRetry(testTxRetryConfig(5)))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+
+	expected := []string{"BEGIN", "ROLLBACK", "BEGIN", "ROLLBACK", "BEGIN", "COMMIT"}
+	if events := drv.Events(); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+}
+
+func TestWithTransactionInInstance_RetryGivesUp(t *testing.T) {
+	db, _ := newFakeDB(t, nil)
+
+	calls := 0
+	err := WithTransactionInInstance(context.Background(), db, func(ctx context.Context) error {
+		calls++
+		return &pq.Error{Code: "40001"}
+	}, WithTxRetry(testTxRetryConfig(2)))
+	if !IsSerializationFailure(err) {
+		t.Fatalf("expected serialization failure, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestWithTransactionInInstance_DoesNotRetryOtherErrors(t *testing.T) {
+	db, _ := newFakeDB(t, nil)
+	expectedErr := &pq.Error{Code: "23505"}
+
+	calls := 0
+	err := WithTransactionInInstance(context.Background(), db, func(ctx context.Context) error {
+		calls++
+		return expectedErr
+	}, WithTxRetry(testTxRetryConfig(5)))
+	if err != expectedErr {
+		t.Fatalf("expected %v, got %v", expectedErr, err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestWithTransactionInInstance_WithoutRetry(t *testing.T) {
+	for name, opt := range map[string]TxOption{
+		"WithoutTxRetry":  WithoutTxRetry(),
+		"MaxAttemptsOnly": WithTxRetry(retry.Config{MaxAttempts: 1}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			db, _ := newFakeDB(t, nil)
+
+			calls := 0
+			err := WithTransactionInInstance(context.Background(), db, func(ctx context.Context) error {
+				calls++
+				return &pq.Error{Code: "40001"}
+			}, opt)
+			if !IsSerializationFailure(err) {
+				t.Fatalf("expected serialization failure, got %v", err)
+			}
+			if calls != 1 {
+				t.Fatalf("expected 1 call, got %d", calls)
+			}
+		})
+	}
+}
+
+func TestWithTxRetry_FillsZeroFields(t *testing.T) {
+	var cfg txConfig
+	WithTxRetry(retry.Config{Jitter: 0.5})(&cfg)
+
+	expected := DefaultTxRetryConfig()
+	expected.Jitter = 0.5
+	if cfg.retry != expected {
+		t.Fatalf("expected %+v, got %+v", expected, cfg.retry)
+	}
+}
+
+func TestWithTransactionInInstance_NestedOnOtherInstanceKeepsOuter(t *testing.T) {
+	orders, ordersDrv := newFakeDB(t, nil)
+	audit, auditDrv := newFakeDB(t, nil)
+
+	err := WithTransactionInInstance(context.Background(), orders, func(ctx context.Context) error {
+		return WithTransactionInInstance(ctx, audit, func(ctx context.Context) error {
+			if !InTransactionInInstance(ctx, orders) || !InTransactionInInstance(ctx, audit) {
+				t.Fatal("expected both transactions in context")
+			}
+			if err := NewStatement(ctx, "INSERT INTO orders (id) VALUES ($1)", 1).ExecuteInInstance(orders); err != nil {
+				return err
+			}
+			if err := NewStatement(ctx, "INSERT INTO audit (event) VALUES ($1)", "order").ExecuteInInstance(audit); err != nil {
+				return err
+			}
+
+			return WithTransactionInInstance(ctx, orders, func(ctx context.Context) error {
+				return NewStatement(ctx, "UPDATE orders SET paid = true").ExecuteInInstance(orders)
+			})
+		})
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expectedOrders := []string{"BEGIN", "INSERT INTO orders (id) VALUES ($1)", "SAVEPOINT sdkopen_savepoint_1", "UPDATE orders SET paid = true", "RELEASE SAVEPOINT sdkopen_savepoint_1", "COMMIT"}
+	if events := ordersDrv.Events(); !reflect.DeepEqual(events, expectedOrders) {
+		t.Fatalf("expected orders events %v, got %v", expectedOrders, events)
+	}
+	expectedAudit := []string{"BEGIN", "INSERT INTO audit (event) VALUES ($1)", "COMMIT"}
+	if events := auditDrv.Events(); !reflect.DeepEqual(events, expectedAudit) {
+		t.Fatalf("expected audit events %v, got %v", expectedAudit, events)
+	}
+}
+
+func testTxRetryConfig(attempts int) retry.Config {
+	return retry.Config{MaxAttempts: attempts, InitialInterval: time.Millisecond, Multiplier: 1}
+}
@@ -8,9 +8,37 @@ import (
 
 type HandlerFunc func(ctx context.Context, msg Message) error
 
+const (
+	DefaultConcurrency int = 10
+	DefaultPrefetch    int = 2 * DefaultConcurrency
+)
+
 type Subscription struct {
 	Topic   string
 	Handler HandlerFunc
+	Retry   RetryPolicy
+	// Prefetch is how many unacked messages the broker hands to the consumer.
+	Prefetch int
+	// Concurrency is how many messages are handled at the same time.
+	Concurrency int
+}
+
+type SubscriptionOption func(*Subscription)
+
+// withDefaults fills the zero fields of a subscription that did not go
+// through Subscribe, such as one passed straight to Consumer.Subscribe.
+func (s Subscription) withDefaults() Subscription {
+	if s.Retry.MaxAttempts == 0 && len(s.Retry.Delays) == 0 {
+		s.Retry = DefaultRetryPolicy()
+	}
+	if s.Prefetch <= 0 {
+		s.Prefetch = DefaultPrefetch
+	}
+	if s.Concurrency <= 0 {
+		s.Concurrency = DefaultConcurrency
+	}
+
+	return s
 }
 
 type Consumer interface {
@@ -24,8 +52,31 @@ var (
 	subscriptions    []Subscription
 )
 
-func Subscribe(topic string, handler HandlerFunc) {
-	subscriptions = append(subscriptions, Subscription{topic, handler})
+func Subscribe(topic string, handler HandlerFunc, opts ...SubscriptionOption) {
+	subscription := Subscription{
+		Topic:       topic,
+		Handler:     handler,
+		Retry:       DefaultRetryPolicy(),
+		Prefetch:    DefaultPrefetch,
+		Concurrency: DefaultConcurrency,
+	}
+	for _, opt := range opts {
+		opt(&subscription)
+	}
+
+	subscriptions = append(subscriptions, subscription)
+}
+
+func WithPrefetch(count int) SubscriptionOption {
+	return func(s *Subscription) {
+		s.Prefetch = count
+	}
+}
+
+func WithConcurrency(workers int) SubscriptionOption {
+	return func(s *Subscription) {
+		s.Concurrency = workers
+	}
 }
 
 func StartConsumer() {
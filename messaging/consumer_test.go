@@ -2,7 +2,9 @@ package messaging
 
 import (
 	"context"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestSubscribe_AddsSubscription(t *testing.T) {
@@ -51,6 +53,42 @@ func TestSubscribe_MultipleTopics(t *testing.T) {
 	}
 }
 
+func TestSubscribe_RetryPolicy(t *testing.T) {
+	subscriptions = nil
+
+	handler := func(ctx context.Context, msg Message) error {
+		return nil
+	}
+
+	Subscribe("default-retry", handler)
+	Subscribe("custom-retry", handler, WithRetry(RetryPolicy{MaxAttempts: 10, Delays: []time.Duration{time.Minute}}))
+
+	if !reflect.DeepEqual(subscriptions[0].Retry, DefaultRetryPolicy()) {
+		t.Fatalf("expected default retry policy, got %+v", subscriptions[0].Retry)
+	}
+	if subscriptions[1].Retry.MaxAttempts != 10 || subscriptions[1].Retry.delay(1) != time.Minute {
+		t.Fatalf("expected custom retry policy, got %+v", subscriptions[1].Retry)
+	}
+}
+
+func TestSubscribe_PrefetchAndConcurrency(t *testing.T) {
+	subscriptions = nil
+
+	handler := func(ctx context.Context, msg Message) error {
+		return nil
+	}
+
+	Subscribe("defaults", handler)
+	Subscribe("custom", handler, WithPrefetch(50), WithConcurrency(25))
+
+	if subscriptions[0].Prefetch != DefaultPrefetch || subscriptions[0].Concurrency != DefaultConcurrency {
+		t.Fatalf("expected default prefetch and concurrency, got %+v", subscriptions[0])
+	}
+	if subscriptions[1].Prefetch != 50 || subscriptions[1].Concurrency != 25 {
+		t.Fatalf("expected custom prefetch and concurrency, got %+v", subscriptions[1])
+	}
+}
+
 func TestSubscription_Struct(t *testing.T) {
 	handler := func(ctx context.Context, msg Message) error {
 		return nil
@@ -8,6 +8,8 @@ type Message struct {
 	Body      []byte
 	Headers   map[string]string
 	Timestamp time.Time
+	// Attempt starts at 1 and grows each time the message is retried.
+	Attempt int
 }
 
 type PublishOption func(*publishConfig)
@@ -36,3 +38,10 @@ func applyOptions(opts []PublishOption) publishConfig {
 	}
 	return cfg
 }
+
+// ResolvePublishOptions returns the headers and delay set by opts, for
+// publishers that store messages to send them later.
+func ResolvePublishOptions(opts ...PublishOption) (map[string]string, int) {
+	cfg := applyOptions(opts)
+	return cfg.Headers, cfg.DelaySeconds
+}
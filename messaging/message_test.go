@@ -86,6 +86,17 @@ func TestApplyOptions_LastOptionWins(t *testing.T) {
 	}
 }
 
+func TestResolvePublishOptions(t *testing.T) {
+	headers, delay := ResolvePublishOptions(WithHeaders(map[string]string{"key": "val"}), WithDelay(30))
+
+	if headers["key"] != "val" {
+		t.Fatalf("expected key=val, got %s", headers["key"])
+	}
+	if delay != 30 {
+		t.Fatalf("expected delay=30, got %d", delay)
+	}
+}
+
 func TestMessage_Struct(t *testing.T) {
 	msg := Message{
 		ID:    "msg-123",
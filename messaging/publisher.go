@@ -1,6 +1,11 @@
 package messaging
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+const publisherNotInitializedErrorMsg string = "messaging publisher not initialized"
 
 type Publisher interface {
 	Publish(ctx context.Context, topic string, body []byte, opts ...PublishOption) error
@@ -10,5 +15,9 @@ type Publisher interface {
 var publisherInstance Publisher
 
 func Publish(ctx context.Context, topic string, body []byte, opts ...PublishOption) error {
+	if publisherInstance == nil {
+		return errors.New(publisherNotInitializedErrorMsg)
+	}
+
 	return publisherInstance.Publish(ctx, topic, body, opts...)
 }
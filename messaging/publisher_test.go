@@ -0,0 +1,15 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPublish_NotInitialized(t *testing.T) {
+	publisherInstance = nil
+
+	err := Publish(context.Background(), "orders", []byte("{}"))
+	if err == nil || err.Error() != publisherNotInitializedErrorMsg {
+		t.Fatalf("expected '%s', got %v", publisherNotInitializedErrorMsg, err)
+	}
+}
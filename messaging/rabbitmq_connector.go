@@ -1,9 +1,11 @@
 package messaging
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sdkopen/sdkopen-go/common/env"
+	"github.com/sdkopen/sdkopen-go/common/retry"
 	"github.com/sdkopen/sdkopen-go/logging"
 
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -17,25 +19,70 @@ const (
 )
 
 type RabbitMQConnector struct {
-	host     string
-	port     int
-	username string
-	password string
-	vhost    string
+	host          string
+	port          int
+	username      string
+	password      string
+	vhost         string
+	retry         retry.Config
+	reconnect     retry.Config
+	publishBuffer int
 }
 
-func NewDefaultRabbitMQConnector() *RabbitMQConnector {
-	return &RabbitMQConnector{
-		host:     env.RABBITMQ_URL,
-		port:     env.RABBITMQ_PORT,
-		username: env.RABBITMQ_USERNAME,
-		password: env.RABBITMQ_PASSWORD,
-		vhost:    env.RABBITMQ_VHOST,
+type RabbitMQOption func(*RabbitMQConnector)
+
+func WithConnectRetry(cfg retry.Config) RabbitMQOption {
+	return func(c *RabbitMQConnector) {
+		c.retry = cfg
+	}
+}
+
+// WithReconnectRetry sets the backoff used to dial again after the
+// connection is lost. By default it uses the connect intervals and never
+// gives up.
+func WithReconnectRetry(cfg retry.Config) RabbitMQOption {
+	return func(c *RabbitMQConnector) {
+		c.reconnect = cfg
+	}
+}
+
+// WithPublishBuffer sets how many publishes are kept in memory while the
+// publisher is disconnected, to be sent once it reconnects. With 0, publishes
+// fail fast with ErrPublisherDisconnected.
+func WithPublishBuffer(size int) RabbitMQOption {
+	return func(c *RabbitMQConnector) {
+		c.publishBuffer = size
+	}
+}
+
+func NewDefaultRabbitMQConnector(opts ...RabbitMQOption) *RabbitMQConnector {
+	connector := &RabbitMQConnector{
+		host:          env.RABBITMQ_URL,
+		port:          env.RABBITMQ_PORT,
+		username:      env.RABBITMQ_USERNAME,
+		password:      env.RABBITMQ_PASSWORD,
+		vhost:         env.RABBITMQ_VHOST,
+		retry:         retry.DefaultConfig(),
+		reconnect:     defaultReconnectConfig(),
+		publishBuffer: env.RABBITMQ_PUBLISH_BUFFER,
+	}
+
+	for _, opt := range opts {
+		opt(connector)
 	}
+
+	return connector
 }
 
 func (c *RabbitMQConnector) Connect() *amqp.Connection {
-	conn, err := amqp.Dial(c.getConnectionURI())
+	var conn *amqp.Connection
+	operation := fmt.Sprintf("connecting to rabbitmq at %s:%d", c.host, c.port)
+
+	err := retry.Do(context.Background(), operation, c.retry, func() error {
+		var err error
+		conn, err = amqp.Dial(c.getConnectionURI())
+		return err
+	})
 	if err != nil {
 		logging.Fatal(rabbitConnectionErrorMsg, err)
 	}
@@ -44,6 +91,23 @@ func (c *RabbitMQConnector) Connect() *amqp.Connection {
 	return conn
 }
 
+func (c *RabbitMQConnector) dial() (rabbitConnection, error) {
+	conn, err := amqp.Dial(c.getConnectionURI())
+	if err != nil {
+		return nil, err
+	}
+	return amqpConnection{conn}, nil
+}
+
+// defaultReconnectConfig never gives up: once started, the service has no
+// other way back to the broker.
+func defaultReconnectConfig() retry.Config {
+	cfg := retry.DefaultConfig()
+	cfg.MaxAttempts = 0
+	cfg.MaxWait = 0
+	return cfg
+}
+
 func (c *RabbitMQConnector) getConnectionURI() string {
 	return fmt.Sprintf(defaultConnectionURI,
 		c.username,
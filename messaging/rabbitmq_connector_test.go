@@ -2,8 +2,10 @@ package messaging
 
 import (
 	"testing"
+	"time"
 
 	"github.com/sdkopen/sdkopen-go/common/env"
+	"github.com/sdkopen/sdkopen-go/common/retry"
 )
 
 func TestNewDefaultRabbitMQConnector(t *testing.T) {
@@ -32,6 +34,42 @@ func TestNewDefaultRabbitMQConnector(t *testing.T) {
 	}
 }
 
+func TestNewDefaultRabbitMQConnector_WithConnectRetry(t *testing.T) {
+	cfg := retry.Config{MaxAttempts: 5, InitialInterval: time.Second}
+
+	connector := NewDefaultRabbitMQConnector(WithConnectRetry(cfg))
+
+	if connector.retry != cfg {
+		t.Fatalf("expected retry config %+v, got %+v", cfg, connector.retry)
+	}
+}
+
+func TestNewDefaultRabbitMQConnector_ReconnectDefaults(t *testing.T) {
+	env.RABBITMQ_PUBLISH_BUFFER = 0
+
+	connector := NewDefaultRabbitMQConnector()
+
+	if connector.reconnect.MaxAttempts != 0 || connector.reconnect.MaxWait != 0 {
+		t.Fatalf("expected reconnection without limit, got %+v", connector.reconnect)
+	}
+	if connector.publishBuffer != 0 {
+		t.Fatalf("expected fail fast publishes by default, got buffer %d", connector.publishBuffer)
+	}
+}
+
+func TestNewDefaultRabbitMQConnector_WithReconnectOptions(t *testing.T) {
+	cfg := retry.Config{MaxAttempts: 10, InitialInterval: time.Second}
+
+	connector := NewDefaultRabbitMQConnector(WithReconnectRetry(cfg), WithPublishBuffer(1000))
+
+	if connector.reconnect != cfg {
+		t.Fatalf("expected reconnect config %+v, got %+v", cfg, connector.reconnect)
+	}
+	if connector.publishBuffer != 1000 {
+		t.Fatalf("expected publish buffer 1000, got %d", connector.publishBuffer)
+	}
+}
+
 func TestRabbitMQConnector_GetConnectionURI(t *testing.T) {
 	connector := &RabbitMQConnector{
 		host:     "mq.example.com",
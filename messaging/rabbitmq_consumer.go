@@ -2,7 +2,9 @@ package messaging
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/sdkopen/sdkopen-go/common/observer"
@@ -11,27 +13,35 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// errConsumerDisconnected is logged when a failed message cannot be retried
+// because the connection is down; the broker redelivers it after reconnecting.
+var errConsumerDisconnected = errors.New("rabbitmq consumer is disconnected")
+
 type RabbitMQConsumer struct {
-	conn          *amqp.Connection
-	channel       *amqp.Channel
+	connection    *reconnector
+	mx            sync.RWMutex
+	channel       rabbitChannel
 	subscriptions []Subscription
+	started       bool
 	done          chan struct{}
 }
 
 func CreateRabbitMQConsumer() Consumer {
-	connector := NewDefaultRabbitMQConnector()
-	conn := connector.Connect()
+	return NewRabbitMQConsumer(NewDefaultRabbitMQConnector())
+}
 
-	ch, err := conn.Channel()
-	if err != nil {
+// NewRabbitMQConsumer connects a consumer with the settings of connector.
+// After a reconnection it declares and consumes every subscription again.
+func NewRabbitMQConsumer(connector *RabbitMQConnector) Consumer {
+	consumer := &RabbitMQConsumer{done: make(chan struct{})}
+	consumer.connection = newReconnector("consumer", connector.dial, connector.reconnect, consumer.setup, consumer.disconnect)
+
+	conn := amqpConnection{connector.Connect()}
+	if err := consumer.connection.start(conn); err != nil {
 		logging.Fatal("failed to open rabbitmq channel: %+v", err)
 	}
 
-	return &RabbitMQConsumer{
-		conn:    conn,
-		channel: ch,
-		done:    make(chan struct{}),
-	}
+	return consumer
 }
 
 func (c *RabbitMQConsumer) Subscribe(subscription Subscription) {
@@ -39,17 +49,90 @@ func (c *RabbitMQConsumer) Subscribe(subscription Subscription) {
 }
 
 func (c *RabbitMQConsumer) Start() error {
+	c.mx.Lock()
+	c.started = true
+	var err error
+	// While disconnected, the subscriptions are consumed on reconnection.
+	if c.channel != nil {
+		err = c.consumeAll()
+	}
+	c.mx.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	<-c.done
+	return nil
+}
+
+// consumeAll must be called with mx held.
+func (c *RabbitMQConsumer) consumeAll() error {
 	for _, sub := range c.subscriptions {
 		if err := c.consume(sub); err != nil {
 			return fmt.Errorf("failed to start consumer for %s: %w", sub.Topic, err)
 		}
 	}
 
-	<-c.done
 	return nil
 }
 
+// setup opens the channel of a new connection and watches it, so a channel
+// closed by the broker, or whose consumers were cancelled, is reopened on the
+// same connection.
+func (c *RabbitMQConsumer) setup(conn rabbitConnection) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf(channelOpenErrorMsg, err)
+	}
+
+	if err = c.useChannel(ch); err != nil {
+		_ = ch.Close()
+		return err
+	}
+
+	c.connection.watchChannel(conn, ch, c.useChannel)
+	return nil
+}
+
+// useChannel declares and consumes the subscriptions on ch once the consumer
+// was started. The workers of the previous channel stop when its deliveries
+// are closed with it.
+func (c *RabbitMQConsumer) useChannel(ch rabbitChannel) error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.channel = ch
+	if !c.started {
+		return nil
+	}
+
+	if err := c.consumeAll(); err != nil {
+		c.channel = nil
+		return err
+	}
+
+	return nil
+}
+
+func (c *RabbitMQConsumer) disconnect() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.channel = nil
+}
+
+func (c *RabbitMQConsumer) currentChannel() rabbitChannel {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	return c.channel
+}
+
+// consume must be called with mx held.
 func (c *RabbitMQConsumer) consume(sub Subscription) error {
+	sub = sub.withDefaults()
+
 	err := c.channel.ExchangeDeclare(
 		sub.Topic,
 		"topic",
@@ -86,6 +169,15 @@ func (c *RabbitMQConsumer) consume(sub Subscription) error {
 		return fmt.Errorf("failed to bind queue %s: %w", sub.Topic, err)
 	}
 
+	if err = c.declareRetryTopology(sub, q.Name); err != nil {
+		return err
+	}
+
+	// Without global, the prefetch applies to each consumer started after it.
+	if err = c.channel.Qos(sub.Prefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set prefetch for queue %s: %w", sub.Topic, err)
+	}
+
 	deliveries, err := c.channel.Consume(
 		q.Name,
 		"",
@@ -99,51 +191,65 @@ func (c *RabbitMQConsumer) consume(sub Subscription) error {
 		return fmt.Errorf("failed to consume from queue %s: %w", sub.Topic, err)
 	}
 
-	go func() {
-		for d := range deliveries {
-			wg := observer.GetWaitGroup()
-			wg.Add(1)
+	for range sub.Concurrency {
+		go func() {
+			for d := range deliveries {
+				c.handleTracked(sub, q.Name, d)
+			}
+		}()
+	}
+
+	logging.Info("consuming messages from topic: %s (prefetch %d, concurrency %d)", sub.Topic, sub.Prefetch, sub.Concurrency)
+	return nil
+}
 
-			go func(delivery amqp.Delivery) {
-				defer wg.Done()
+// handleTracked keeps the delivery in the shutdown WaitGroup while it is
+// handled.
+func (c *RabbitMQConsumer) handleTracked(sub Subscription, queue string, delivery amqp.Delivery) {
+	wg := observer.GetWaitGroup()
+	wg.Add(1)
+	defer wg.Done()
 
-				msg := Message{
-					ID:        delivery.MessageId,
-					Topic:     sub.Topic,
-					Body:      delivery.Body,
-					Headers:   extractHeaders(delivery.Headers),
-					Timestamp: delivery.Timestamp,
-				}
+	c.handle(sub, queue, delivery)
+}
 
-				if msg.Timestamp.IsZero() {
-					msg.Timestamp = time.Now()
-				}
+func (c *RabbitMQConsumer) handle(sub Subscription, queue string, delivery amqp.Delivery) {
+	msg := Message{
+		ID:        delivery.MessageId,
+		Topic:     sub.Topic,
+		Body:      delivery.Body,
+		Headers:   extractHeaders(delivery.Headers),
+		Timestamp: delivery.Timestamp,
+		Attempt:   previousAttempts(delivery.Headers) + 1,
+	}
 
-				if err := sub.Handler(context.Background(), msg); err != nil {
-					logging.Error("error handling message on topic %s: %v", sub.Topic, err)
-					_ = delivery.Nack(false, true)
-					return
-				}
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
 
-				_ = delivery.Ack(false)
-			}(d)
-		}
-	}()
+	if err := sub.Handler(context.Background(), msg); err != nil {
+		logging.Error("error handling message on topic %s: %v", sub.Topic, err)
+		c.retry(sub, queue, delivery, msg.Attempt, err)
+		return
+	}
 
-	logging.Info("consuming messages from topic: %s", sub.Topic)
-	return nil
+	_ = delivery.Ack(false)
 }
 
 func (c *RabbitMQConsumer) Close() error {
 	close(c.done)
 
+	c.mx.Lock()
 	if c.channel != nil {
 		if err := c.channel.Close(); err != nil {
 			logging.Error("error closing rabbitmq consumer channel: %v", err)
 		}
+		c.channel = nil
 	}
-	if c.conn != nil {
-		return c.conn.Close()
+	c.mx.Unlock()
+
+	if c.connection != nil {
+		return c.connection.close()
 	}
 	return nil
 }
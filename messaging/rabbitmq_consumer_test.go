@@ -1,7 +1,11 @@
 package messaging
 
 import (
+	"context"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
@@ -62,3 +66,94 @@ func TestExtractHeaders_NilTable(t *testing.T) {
 		t.Fatalf("expected 0 headers, got %d", len(headers))
 	}
 }
+
+func TestRabbitMQConsumer_PrefetchAndConcurrency(t *testing.T) {
+	channel := newFakeChannel()
+	channel.deliveries = make(chan amqp.Delivery)
+	consumer := &RabbitMQConsumer{channel: channel}
+
+	var running, peak atomic.Int32
+	var handled sync.WaitGroup
+	release := make(chan struct{})
+	sub := Subscription{
+		Topic:       "orders",
+		Retry:       DefaultRetryPolicy(),
+		Prefetch:    8,
+		Concurrency: 3,
+		Handler: func(context.Context, Message) error {
+			defer handled.Done()
+			current := running.Add(1)
+			for {
+				previous := peak.Load()
+				if current <= previous || peak.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			<-release
+			running.Add(-1)
+			return nil
+		},
+	}
+
+	if err := consumer.consume(sub); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if channel.prefetch != 8 {
+		t.Fatalf("expected prefetch 8, got %d", channel.prefetch)
+	}
+
+	handled.Add(6)
+	acks := make([]*fakeAcknowledger, 6)
+	go func() {
+		for i := range acks {
+			delivery, ack := newDelivery(nil)
+			acks[i] = ack
+			channel.deliveries <- delivery
+		}
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for running.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if running.Load() != 3 {
+		t.Fatalf("expected 3 messages in flight, got %d", running.Load())
+	}
+
+	close(release)
+	handled.Wait()
+	close(channel.deliveries)
+
+	if peak.Load() != 3 {
+		t.Fatalf("expected at most 3 concurrent handlers, got %d", peak.Load())
+	}
+}
+
+func TestRabbitMQConsumer_ZeroSubscriptionUsesDefaults(t *testing.T) {
+	channel := newFakeChannel()
+	consumer := &RabbitMQConsumer{channel: channel}
+
+	if err := consumer.consume(Subscription{Topic: "orders", Handler: func(context.Context, Message) error { return nil }}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if channel.prefetch != DefaultPrefetch {
+		t.Fatalf("expected prefetch %d, got %d", DefaultPrefetch, channel.prefetch)
+	}
+	if _, ok := channel.queues["orders.dlq"]; !ok {
+		t.Fatalf("expected dead-letter queue of the default retry policy, got %v", channel.queues)
+	}
+	if _, ok := channel.queues["orders.retry.1s"]; !ok {
+		t.Fatalf("expected wait queues of the default retry policy, got %v", channel.queues)
+	}
+}
+
+func TestSubscription_WithDefaultsKeepsExplicitValues(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 2}
+	sub := Subscription{Retry: policy, Prefetch: 4, Concurrency: 1}.withDefaults()
+
+	if sub.Retry.MaxAttempts != 2 || sub.Retry.Delays != nil || sub.Prefetch != 4 || sub.Concurrency != 1 {
+		t.Fatalf("expected explicit values kept, got %+v", sub)
+	}
+}
@@ -0,0 +1,113 @@
+package messaging
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/sdkopen/sdkopen-go/logging"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	delayedExchangeType   string = "x-delayed-message"
+	delayedExchangeSuffix string = ".delayed"
+	delayQueueSuffix      string = ".delay."
+
+	delayPluginMsg      string = "rabbitmq delayed message plugin available, delayed messages use %s exchanges"
+	delayFallbackMsg    string = "rabbitmq delayed message plugin not available, delayed messages use TTL queues: %v"
+	delayProbeErrorMsg  string = "failed to check for the rabbitmq delayed message plugin: %w"
+	delayDeclareErrMsg  string = "failed to declare delayed route for %s: %w"
+	delayChannelWarnMsg string = "error closing rabbitmq probe channel: %v"
+)
+
+type delayMode int
+
+const (
+	delayUndetected delayMode = iota
+	delayPlugin
+	delayTTL
+)
+
+// delayStrategy remembers whether the broker has the delayed message plugin,
+// which is checked on the first delayed publish.
+type delayStrategy struct {
+	mx   sync.Mutex
+	mode delayMode
+}
+
+// delayedRoute returns where a delayed message must be published. With the
+// plugin, the topic gets a companion x-delayed-message exchange bound to it,
+// as the existing topic exchange cannot change its type. Without it, the
+// message waits in a TTL queue that dead-letters into the topic exchange.
+func (p *RabbitMQPublisher) delayedRoute(ch rabbitChannel, topic string, seconds int) (string, string, error) {
+	mode, err := p.delayMode(topic)
+	if err != nil {
+		return "", "", err
+	}
+
+	if mode == delayPlugin {
+		exchange := topic + delayedExchangeSuffix
+		if err = declareDelayedExchange(ch, topic); err != nil {
+			return "", "", fmt.Errorf(delayDeclareErrMsg, topic, err)
+		}
+		return exchange, topic, nil
+	}
+
+	queue := topic + delayQueueSuffix + strconv.Itoa(seconds) + "s"
+	_, err = ch.QueueDeclare(queue, true, false, false, false, amqp.Table{
+		"x-message-ttl":             int64(seconds) * 1000,
+		"x-dead-letter-exchange":    topic,
+		"x-dead-letter-routing-key": topic,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf(delayDeclareErrMsg, topic, err)
+	}
+
+	return "", queue, nil
+}
+
+func (p *RabbitMQPublisher) delayMode(topic string) (delayMode, error) {
+	p.delay.mx.Lock()
+	defer p.delay.mx.Unlock()
+
+	if p.delay.mode != delayUndetected {
+		return p.delay.mode, nil
+	}
+
+	// A failed declare closes the channel, so the check declares the delayed
+	// exchange of the topic on a channel of its own.
+	probe, err := p.openChannel()
+	if err != nil {
+		return delayUndetected, fmt.Errorf(delayProbeErrorMsg, err)
+	}
+
+	err = declareDelayedExchange(probe, topic)
+	var amqpErr *amqp.Error
+	switch {
+	case err == nil:
+		p.delay.mode = delayPlugin
+		logging.Info(delayPluginMsg, delayedExchangeType)
+		if closeErr := probe.Close(); closeErr != nil {
+			logging.Warn(delayChannelWarnMsg, closeErr)
+		}
+	case errors.As(err, &amqpErr) && amqpErr.Code == amqp.CommandInvalid:
+		p.delay.mode = delayTTL
+		logging.Warn(delayFallbackMsg, err)
+	default:
+		return delayUndetected, fmt.Errorf(delayProbeErrorMsg, err)
+	}
+
+	return p.delay.mode, nil
+}
+
+func declareDelayedExchange(ch rabbitChannel, topic string) error {
+	exchange := topic + delayedExchangeSuffix
+	if err := ch.ExchangeDeclare(exchange, delayedExchangeType, true, false, false, false, amqp.Table{"x-delayed-type": "topic"}); err != nil {
+		return err
+	}
+
+	return ch.ExchangeBind(topic, "#", exchange, false, nil)
+}
@@ -2,37 +2,109 @@ package messaging
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/sdkopen/sdkopen-go/logging"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+const (
+	publishErrorMsg     string = "failed to publish message to %s: %w"
+	bufferFlushedMsg    string = "sent %d messages buffered while the rabbitmq publisher was disconnected"
+	bufferDropErrorMsg  string = "dropping message buffered for %s: %v"
+	bufferDroppedMsg    string = "dropping %d messages buffered while the rabbitmq publisher was disconnected"
+	bufferFlushErrorMsg string = "failed to send buffered messages: %w"
+)
+
+var (
+	// ErrPublisherDisconnected is returned by Publish while the broker
+	// connection is down and no publish buffer is configured.
+	ErrPublisherDisconnected = errors.New("rabbitmq publisher is disconnected")
+	// ErrPublishBufferFull is returned by Publish while the broker connection
+	// is down and the publish buffer has no room left.
+	ErrPublishBufferFull = errors.New("rabbitmq publish buffer is full")
+)
+
 type RabbitMQPublisher struct {
-	conn    *amqp.Connection
-	channel *amqp.Channel
+	connection  *reconnector
+	mx          sync.RWMutex
+	channel     rabbitChannel
+	openChannel func() (rabbitChannel, error)
+	delay       delayStrategy
+	bufferSize  int
+	buffer      []bufferedPublish
+	closed      bool
+}
+
+type bufferedPublish struct {
+	topic string
+	body  []byte
+	opts  []PublishOption
 }
 
 func CreateRabbitMQPublisher() Publisher {
-	connector := NewDefaultRabbitMQConnector()
-	conn := connector.Connect()
+	return NewRabbitMQPublisher(NewDefaultRabbitMQConnector())
+}
 
-	ch, err := conn.Channel()
-	if err != nil {
+// NewRabbitMQPublisher connects a publisher with the settings of connector,
+// including how it reconnects and buffers publishes during an outage.
+func NewRabbitMQPublisher(connector *RabbitMQConnector) Publisher {
+	publisher := &RabbitMQPublisher{bufferSize: connector.publishBuffer}
+	publisher.connection = newReconnector("publisher", connector.dial, connector.reconnect, publisher.setup, publisher.disconnect)
+	publisher.openChannel = publisher.connection.channel
+
+	conn := amqpConnection{connector.Connect()}
+	if err := publisher.connection.start(conn); err != nil {
 		logging.Fatal("failed to open rabbitmq channel: %+v", err)
 	}
 
-	return &RabbitMQPublisher{
-		conn:    conn,
-		channel: ch,
-	}
+	return publisher
 }
 
 func (p *RabbitMQPublisher) Publish(ctx context.Context, topic string, body []byte, opts ...PublishOption) error {
+	p.mx.RLock()
+	ch := p.channel
+	p.mx.RUnlock()
+
+	if ch != nil {
+		err := p.publish(ctx, ch, topic, body, opts)
+		if !errors.Is(err, amqp.ErrClosed) {
+			return err
+		}
+	}
+
+	return p.publishDisconnected(ctx, ch, topic, body, opts)
+}
+
+// publishDisconnected handles a publish that found no channel, or a closed
+// one. If the publisher reconnected meanwhile, it is sent on the new channel;
+// otherwise it is buffered or fails fast.
+func (p *RabbitMQPublisher) publishDisconnected(ctx context.Context, failed rabbitChannel, topic string, body []byte, opts []PublishOption) error {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	if p.channel != nil && p.channel != failed {
+		return p.publish(ctx, p.channel, topic, body, opts)
+	}
+
+	if p.closed || p.bufferSize <= 0 {
+		return fmt.Errorf(publishErrorMsg, topic, ErrPublisherDisconnected)
+	}
+	if len(p.buffer) >= p.bufferSize {
+		return fmt.Errorf(publishErrorMsg, topic, ErrPublishBufferFull)
+	}
+
+	p.buffer = append(p.buffer, bufferedPublish{topic: topic, body: body, opts: opts})
+	return nil
+}
+
+func (p *RabbitMQPublisher) publish(ctx context.Context, ch rabbitChannel, topic string, body []byte, opts []PublishOption) error {
 	cfg := applyOptions(opts)
 
-	err := p.channel.ExchangeDeclare(
+	err := ch.ExchangeDeclare(
 		topic,
 		"topic",
 		true,
@@ -49,14 +121,19 @@ func (p *RabbitMQPublisher) Publish(ctx context.Context, topic string, body []by
 	for k, v := range cfg.Headers {
 		headers[k] = v
 	}
+
+	exchange, key := topic, topic
 	if cfg.DelaySeconds > 0 {
 		headers["x-delay"] = int32(cfg.DelaySeconds * 1000)
+		if exchange, key, err = p.delayedRoute(ch, topic, cfg.DelaySeconds); err != nil {
+			return err
+		}
 	}
 
-	err = p.channel.PublishWithContext(
+	err = ch.PublishWithContext(
 		ctx,
-		topic,
-		topic,
+		exchange,
+		key,
 		false,
 		false,
 		amqp.Publishing{
@@ -66,20 +143,79 @@ func (p *RabbitMQPublisher) Publish(ctx context.Context, topic string, body []by
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("failed to publish message to %s: %w", topic, err)
+		return fmt.Errorf(publishErrorMsg, topic, err)
+	}
+
+	return nil
+}
+
+// setup opens the channel of a new connection and watches it, so a channel
+// closed by the broker is reopened on the same connection.
+func (p *RabbitMQPublisher) setup(conn rabbitConnection) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf(channelOpenErrorMsg, err)
+	}
+
+	if err = p.useChannel(ch); err != nil {
+		return err
 	}
 
+	p.connection.watchChannel(conn, ch, p.useChannel)
 	return nil
 }
 
+// useChannel sends the buffered publishes on ch, in order, before any new
+// publish goes through. A message the broker refuses is dropped; a closed
+// channel keeps the rest buffered for the next channel.
+func (p *RabbitMQPublisher) useChannel(ch rabbitChannel) error {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	sent := 0
+	for len(p.buffer) > 0 {
+		msg := p.buffer[0]
+		if err := p.publish(context.Background(), ch, msg.topic, msg.body, msg.opts); errors.Is(err, amqp.ErrClosed) {
+			return fmt.Errorf(bufferFlushErrorMsg, err)
+		} else if err != nil {
+			logging.Error(bufferDropErrorMsg, msg.topic, err)
+		} else {
+			sent++
+		}
+		p.buffer = p.buffer[1:]
+	}
+	if sent > 0 {
+		logging.Info(bufferFlushedMsg, sent)
+	}
+
+	p.channel = ch
+	return nil
+}
+
+func (p *RabbitMQPublisher) disconnect() {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	p.channel = nil
+}
+
 func (p *RabbitMQPublisher) Close() error {
+	p.mx.Lock()
 	if p.channel != nil {
 		if err := p.channel.Close(); err != nil {
 			logging.Error("error closing rabbitmq publisher channel: %v", err)
 		}
+		p.channel = nil
+	}
+	if len(p.buffer) > 0 {
+		logging.Warn(bufferDroppedMsg, len(p.buffer))
+		p.buffer = nil
 	}
-	if p.conn != nil {
-		return p.conn.Close()
+	p.closed = true
+	p.mx.Unlock()
+
+	if p.connection != nil {
+		return p.connection.close()
 	}
 	return nil
 }
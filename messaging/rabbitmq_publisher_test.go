@@ -0,0 +1,95 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func newTestPublisher(pluginAvailable bool) (*RabbitMQPublisher, *fakeChannel, *[]*fakeChannel) {
+	unsupported := map[string]bool{delayedExchangeType: !pluginAvailable}
+	channel := newFakeChannel()
+	channel.unsupported = unsupported
+
+	var probes []*fakeChannel
+	publisher := &RabbitMQPublisher{
+		channel: channel,
+		openChannel: func() (rabbitChannel, error) {
+			probe := newFakeChannel()
+			probe.unsupported = unsupported
+			probes = append(probes, probe)
+			return probe, nil
+		},
+	}
+
+	return publisher, channel, &probes
+}
+
+func TestRabbitMQPublisher_PublishWithoutDelay(t *testing.T) {
+	publisher, channel, probes := newTestPublisher(true)
+
+	if err := publisher.Publish(context.Background(), "orders", []byte("{}")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	published := channel.published[0]
+	if published.exchange != "orders" || published.key != "orders" || len(*probes) != 0 {
+		t.Fatalf("expected plain publish to the topic exchange, got %+v", published)
+	}
+}
+
+func TestRabbitMQPublisher_PublishWithDelayPlugin(t *testing.T) {
+	publisher, channel, probes := newTestPublisher(true)
+
+	for range 2 {
+		if err := publisher.Publish(context.Background(), "orders", []byte("{}"), WithDelay(30)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(*probes) != 1 || !(*probes)[0].closed {
+		t.Fatalf("expected plugin to be checked once on its own channel, got %d probes", len(*probes))
+	}
+	if channel.exchanges["orders.delayed"] != delayedExchangeType || !slices.Contains(channel.bindings, "orders.delayed->orders:#") {
+		t.Fatalf("expected delayed exchange bound to the topic exchange, got %v %v", channel.exchanges, channel.bindings)
+	}
+
+	published := channel.published[0]
+	if published.exchange != "orders.delayed" || published.key != "orders" || published.msg.Headers["x-delay"] != int32(30000) {
+		t.Fatalf("expected publish to the delayed exchange, got %+v", published)
+	}
+}
+
+func TestRabbitMQPublisher_PublishWithDelayFallback(t *testing.T) {
+	publisher, channel, probes := newTestPublisher(false)
+
+	if err := publisher.Publish(context.Background(), "orders", []byte("{}"), WithDelay(30)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if channel.closed || len(*probes) != 1 {
+		t.Fatal("expected failed plugin check not to affect the publisher channel")
+	}
+	args := channel.queues["orders.delay.30s"]
+	if args["x-message-ttl"] != int64(30000) || args["x-dead-letter-exchange"] != "orders" || args["x-dead-letter-routing-key"] != "orders" {
+		t.Fatalf("unexpected delay queue arguments: %v", args)
+	}
+
+	published := channel.published[0]
+	if published.exchange != "" || published.key != "orders.delay.30s" {
+		t.Fatalf("expected publish to the delay queue, got %+v", published)
+	}
+}
+
+func TestRabbitMQPublisher_DelayProbeError(t *testing.T) {
+	publisher, _, _ := newTestPublisher(true)
+	publisher.openChannel = func() (rabbitChannel, error) { return nil, errors.New("connection closed") }
+
+	if err := publisher.Publish(context.Background(), "orders", []byte("{}"), WithDelay(5)); err == nil {
+		t.Fatal("expected error when the plugin cannot be checked")
+	}
+	if publisher.delay.mode != delayUndetected {
+		t.Fatal("expected plugin check to be retried on the next publish")
+	}
+}
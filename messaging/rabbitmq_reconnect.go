@@ -0,0 +1,264 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sdkopen/sdkopen-go/common/retry"
+	"github.com/sdkopen/sdkopen-go/logging"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	connectionLostMsg       string = "rabbitmq %s connection lost, reconnecting: %v"
+	reconnectedMsg          string = "rabbitmq %s reconnected"
+	reconnectGaveUpMsg      string = "rabbitmq %s could not reconnect, it stays disconnected: %v"
+	reconnectOperationMsg   string = "reconnecting rabbitmq %s"
+	notConnectedErrorMsg    string = "rabbitmq %s is not connected"
+	channelOpenErrorMsg     string = "failed to open rabbitmq channel: %w"
+	reconnectClosedErrorMsg string = "rabbitmq %s closed while reconnecting"
+	channelLostMsg          string = "rabbitmq %s channel closed by the broker, reopening it: %v"
+	consumerCancelledMsg    string = "rabbitmq %s consumer %s cancelled by the broker, reopening the channel"
+	channelReopenErrorMsg   string = "rabbitmq %s could not reopen its channel, reconnecting: %v"
+)
+
+// reconnector keeps the connection of a publisher or consumer alive. When
+// the broker closes it, lost is called, a new connection is dialed with
+// backoff and handed to setup, which reopens the channels and redeclares
+// what its owner needs. A connection whose setup fails is closed and dialed
+// again.
+type reconnector struct {
+	name  string
+	dial  func() (rabbitConnection, error)
+	retry retry.Config
+	setup func(rabbitConnection) error
+	lost  func()
+
+	mx     sync.Mutex
+	conn   rabbitConnection
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newReconnector(name string, dial func() (rabbitConnection, error), cfg retry.Config, setup func(rabbitConnection) error, lost func()) *reconnector {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &reconnector{
+		name:   name,
+		dial:   dial,
+		retry:  cfg,
+		setup:  setup,
+		lost:   lost,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// start sets up the first connection and watches it from then on.
+func (r *reconnector) start(conn rabbitConnection) error {
+	closed, err := r.use(conn)
+	if err != nil {
+		return err
+	}
+
+	go r.watch(closed)
+	return nil
+}
+
+// use registers for the close notification before setup, so a connection
+// lost during setup is noticed as well. The connection is current while
+// setup runs, so setup can open extra channels. The caller closes conn on
+// error.
+func (r *reconnector) use(conn rabbitConnection) (chan *amqp.Error, error) {
+	closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+
+	r.mx.Lock()
+	if r.ctx.Err() != nil {
+		r.mx.Unlock()
+		return nil, fmt.Errorf(reconnectClosedErrorMsg, r.name)
+	}
+	r.conn = conn
+	r.mx.Unlock()
+
+	if err := r.setup(conn); err != nil {
+		r.mx.Lock()
+		if r.conn == conn {
+			r.conn = nil
+		}
+		r.mx.Unlock()
+		return nil, err
+	}
+
+	return closed, nil
+}
+
+func (r *reconnector) watch(closed chan *amqp.Error) {
+	for closed != nil {
+		var cause *amqp.Error
+		select {
+		case <-r.ctx.Done():
+			return
+		case cause = <-closed:
+		}
+
+		// close cancels the context before closing the connection, so a
+		// notification after that is not a loss.
+		if r.ctx.Err() != nil {
+			return
+		}
+
+		r.mx.Lock()
+		r.conn = nil
+		r.mx.Unlock()
+
+		logging.Warn(connectionLostMsg, r.name, cause)
+		r.lost()
+		closed = r.reconnect()
+	}
+}
+
+// reconnect dials until a connection is set up, returning its close
+// notification, or nil when it gave up or the reconnector was closed.
+func (r *reconnector) reconnect() chan *amqp.Error {
+	var closed chan *amqp.Error
+
+	err := retry.Do(r.ctx, fmt.Sprintf(reconnectOperationMsg, r.name), r.retry, func() error {
+		conn, err := r.dial()
+		if err != nil {
+			return err
+		}
+
+		if closed, err = r.use(conn); err != nil {
+			_ = conn.Close()
+			if r.ctx.Err() != nil {
+				return retry.Permanent(err)
+			}
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		if r.ctx.Err() == nil {
+			logging.Error(reconnectGaveUpMsg, r.name, err)
+		}
+		return nil
+	}
+
+	logging.Info(reconnectedMsg, r.name)
+	return closed
+}
+
+// channel opens an extra channel on the current connection.
+func (r *reconnector) channel() (rabbitChannel, error) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if r.conn == nil {
+		return nil, fmt.Errorf(notConnectedErrorMsg, r.name)
+	}
+
+	return r.conn.Channel()
+}
+
+// watchChannel reopens the channel of conn when the broker closes it, or
+// cancels its consumer, while the connection stays up. use gets the new
+// channel, as setup does; if that fails, the connection is closed so it is
+// dialed and set up again. A channel closed by its owner is not watched
+// anymore.
+func (r *reconnector) watchChannel(conn rabbitConnection, ch rabbitChannel, use func(rabbitChannel) error) {
+	closed := ch.NotifyClose(make(chan *amqp.Error, 1))
+	cancelled := ch.NotifyCancel(make(chan string, 1))
+
+	go func() {
+		if r.channelLost(ch, closed, cancelled) {
+			r.reopen(conn, use)
+		}
+	}()
+}
+
+func (r *reconnector) channelLost(ch rabbitChannel, closed chan *amqp.Error, cancelled chan string) bool {
+	for {
+		select {
+		case <-r.ctx.Done():
+			return false
+		case cause, ok := <-closed:
+			// amqp closes the receiver without an error when the owner
+			// closes the channel.
+			if !ok || cause == nil {
+				return false
+			}
+			logging.Warn(channelLostMsg, r.name, cause)
+			return true
+		case tag, ok := <-cancelled:
+			if !ok {
+				cancelled = nil
+				continue
+			}
+			logging.Warn(consumerCancelledMsg, r.name, tag)
+			_ = ch.Close()
+			return true
+		}
+	}
+}
+
+// reopen is a no-op once conn was replaced or lost, as watch sets up the new
+// connection from scratch.
+func (r *reconnector) reopen(conn rabbitConnection, use func(rabbitChannel) error) {
+	r.mx.Lock()
+	if r.conn != conn || r.ctx.Err() != nil {
+		r.mx.Unlock()
+		return
+	}
+	ch, err := conn.Channel()
+	r.mx.Unlock()
+
+	// The channel went down with the connection, which watch reconnects.
+	if errors.Is(err, amqp.ErrClosed) {
+		return
+	}
+
+	if err == nil {
+		if err = use(ch); err == nil {
+			r.watchChannel(conn, ch, use)
+			return
+		}
+		_ = ch.Close()
+	}
+
+	logging.Error(channelReopenErrorMsg, r.name, err)
+	r.drop(conn)
+}
+
+// drop closes conn if it is still current, so watch dials a new one.
+func (r *reconnector) drop(conn rabbitConnection) {
+	r.mx.Lock()
+	current := r.conn == conn
+	r.mx.Unlock()
+
+	if current {
+		_ = conn.Close()
+	}
+}
+
+// close stops reconnecting and closes the current connection, if any.
+func (r *reconnector) close() error {
+	r.cancel()
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	conn := r.conn
+	r.conn = nil
+	if conn == nil {
+		return nil
+	}
+
+	if err := conn.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,369 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/common/retry"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type fakeConnection struct {
+	mx         sync.Mutex
+	channels   []*fakeChannel
+	channelErr error
+	notify     chan *amqp.Error
+	closed     bool
+}
+
+func (c *fakeConnection) Channel() (rabbitChannel, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.closed {
+		return nil, amqp.ErrClosed
+	}
+	if c.channelErr != nil {
+		return nil, c.channelErr
+	}
+
+	ch := newFakeChannel()
+	c.channels = append(c.channels, ch)
+	return ch, nil
+}
+
+func (c *fakeConnection) NotifyClose(receiver chan *amqp.Error) chan *amqp.Error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.notify = receiver
+	return receiver
+}
+
+func (c *fakeConnection) Close() error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.notify)
+	}
+	return nil
+}
+
+// drop simulates the broker closing the connection.
+func (c *fakeConnection) drop() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.closed = true
+	c.notify <- &amqp.Error{Code: amqp.ConnectionForced, Reason: "CONNECTION_FORCED - broker forced connection closure"}
+	close(c.notify)
+}
+
+func (c *fakeConnection) channel(i int) *fakeChannel {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.channels[i]
+}
+
+func (c *fakeConnection) channelCount() int {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return len(c.channels)
+}
+
+func (c *fakeConnection) isClosed() bool {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.closed
+}
+
+func (c *fakeConnection) failChannels(err error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.channelErr = err
+}
+
+type fakeDialer struct {
+	mx    sync.Mutex
+	conns []*fakeConnection
+	down  atomic.Bool
+	dials int
+}
+
+func (d *fakeDialer) dial() (rabbitConnection, error) {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	d.dials++
+	if d.down.Load() {
+		return nil, errors.New("dial tcp: connection refused")
+	}
+
+	conn := &fakeConnection{}
+	d.conns = append(d.conns, conn)
+	return conn, nil
+}
+
+func (d *fakeDialer) last() *fakeConnection {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	return d.conns[len(d.conns)-1]
+}
+
+func (d *fakeDialer) dialCount() int {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	return d.dials
+}
+
+func testReconnectRetry() retry.Config {
+	return retry.Config{InitialInterval: time.Millisecond, MaxInterval: 5 * time.Millisecond, Multiplier: 2}
+}
+
+func newReconnectingPublisher(t *testing.T, bufferSize int) (*RabbitMQPublisher, *fakeDialer, *fakeConnection) {
+	dialer := &fakeDialer{}
+	publisher := &RabbitMQPublisher{bufferSize: bufferSize}
+	publisher.connection = newReconnector("publisher", dialer.dial, testReconnectRetry(), publisher.setup, publisher.disconnect)
+	publisher.openChannel = publisher.connection.channel
+
+	conn := &fakeConnection{}
+	if err := publisher.connection.start(conn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = publisher.Close() })
+
+	return publisher, dialer, conn
+}
+
+func (p *RabbitMQPublisher) currentChannel() rabbitChannel {
+	p.mx.RLock()
+	defer p.mx.RUnlock()
+
+	return p.channel
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRabbitMQPublisher_ReconnectsAfterConnectionLoss(t *testing.T) {
+	publisher, dialer, conn := newReconnectingPublisher(t, 0)
+	dialer.down.Store(true)
+
+	conn.drop()
+	waitFor(t, func() bool { return dialer.dialCount() >= 2 })
+	dialer.down.Store(false)
+	waitFor(t, func() bool { return publisher.currentChannel() != nil })
+
+	if err := publisher.Publish(context.Background(), "orders", []byte("{}")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	channel := dialer.last().channel(0)
+	if publisher.currentChannel() != channel || len(channel.published) != 1 {
+		t.Fatalf("expected publish on the channel of the new connection, got %+v", channel.published)
+	}
+}
+
+func TestRabbitMQPublisher_FailsFastWhileDisconnected(t *testing.T) {
+	publisher, dialer, conn := newReconnectingPublisher(t, 0)
+	dialer.down.Store(true)
+
+	conn.drop()
+	waitFor(t, func() bool { return publisher.currentChannel() == nil })
+
+	err := publisher.Publish(context.Background(), "orders", []byte("{}"))
+	if !errors.Is(err, ErrPublisherDisconnected) {
+		t.Fatalf("expected ErrPublisherDisconnected, got %v", err)
+	}
+}
+
+func TestRabbitMQPublisher_BuffersWhileDisconnected(t *testing.T) {
+	publisher, dialer, conn := newReconnectingPublisher(t, 2)
+	dialer.down.Store(true)
+
+	conn.drop()
+	waitFor(t, func() bool { return publisher.currentChannel() == nil })
+
+	for _, body := range []string{"first", "second"} {
+		if err := publisher.Publish(context.Background(), "orders", []byte(body)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := publisher.Publish(context.Background(), "orders", []byte("third")); !errors.Is(err, ErrPublishBufferFull) {
+		t.Fatalf("expected ErrPublishBufferFull, got %v", err)
+	}
+
+	dialer.down.Store(false)
+	waitFor(t, func() bool { return publisher.currentChannel() != nil })
+
+	published := dialer.last().channel(0).published
+	if len(published) != 2 || string(published[0].msg.Body) != "first" || string(published[1].msg.Body) != "second" {
+		t.Fatalf("expected buffered messages sent in order, got %+v", published)
+	}
+}
+
+func TestRabbitMQPublisher_BuffersPublishOnClosedChannel(t *testing.T) {
+	publisher, _, _ := newReconnectingPublisher(t, 1)
+	publisher.currentChannel().(*fakeChannel).publishErr = amqp.ErrClosed
+
+	if err := publisher.Publish(context.Background(), "orders", []byte("{}")); err != nil {
+		t.Fatalf("expected publish on a closed channel to be buffered, got %v", err)
+	}
+	if len(publisher.buffer) != 1 {
+		t.Fatalf("expected 1 buffered message, got %d", len(publisher.buffer))
+	}
+}
+
+func newReconnectingConsumer(t *testing.T) (*RabbitMQConsumer, *fakeDialer, *fakeConnection) {
+	dialer := &fakeDialer{}
+	consumer := &RabbitMQConsumer{started: true, done: make(chan struct{})}
+	consumer.connection = newReconnector("consumer", dialer.dial, testReconnectRetry(), consumer.setup, consumer.disconnect)
+	consumer.Subscribe(Subscription{Topic: "orders", Retry: DefaultRetryPolicy(), Prefetch: 5, Concurrency: 1})
+
+	conn := &fakeConnection{}
+	if err := consumer.connection.start(conn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = consumer.Close() })
+
+	return consumer, dialer, conn
+}
+
+func TestRabbitMQConsumer_ResubscribesAfterReconnect(t *testing.T) {
+	consumer, dialer, conn := newReconnectingConsumer(t)
+
+	conn.drop()
+	waitFor(t, func() bool {
+		ch := consumer.currentChannel()
+		return ch != nil && ch != rabbitChannel(conn.channel(0))
+	})
+
+	channel := dialer.last().channel(0)
+	if _, ok := channel.queues["orders"]; !ok || channel.prefetch != 5 {
+		t.Fatalf("expected subscription declared again on the new channel, got queues %v prefetch %d", channel.queues, channel.prefetch)
+	}
+}
+
+func TestRabbitMQPublisher_ReopensChannelClosedByBroker(t *testing.T) {
+	publisher, dialer, conn := newReconnectingPublisher(t, 1)
+	closed := conn.channel(0)
+
+	closed.publishErr = amqp.ErrClosed
+	closed.drop()
+	if err := publisher.Publish(context.Background(), "orders", []byte("buffered")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitFor(t, func() bool { return conn.channelCount() == 2 })
+	waitFor(t, func() bool { return publisher.currentChannel() == rabbitChannel(conn.channel(1)) })
+
+	if err := publisher.Publish(context.Background(), "orders", []byte("new")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	published := conn.channel(1).published
+	if len(published) != 2 || string(published[0].msg.Body) != "buffered" || string(published[1].msg.Body) != "new" {
+		t.Fatalf("expected publishes on the reopened channel, got %+v", published)
+	}
+	if dialer.dialCount() != 0 || conn.isClosed() {
+		t.Fatalf("expected the connection kept, got %d dials", dialer.dialCount())
+	}
+}
+
+func TestRabbitMQPublisher_ReconnectsWhenChannelCannotBeReopened(t *testing.T) {
+	publisher, dialer, conn := newReconnectingPublisher(t, 0)
+
+	conn.failChannels(errors.New("channel_max reached"))
+	conn.channel(0).drop()
+	waitFor(t, func() bool { return dialer.dialCount() == 1 })
+	waitFor(t, func() bool { return publisher.currentChannel() == rabbitChannel(dialer.last().channel(0)) })
+
+	if !conn.isClosed() {
+		t.Fatal("expected the connection closed after failing to reopen the channel")
+	}
+}
+
+func TestRabbitMQPublisher_DoesNotReopenChannelClosedOnClose(t *testing.T) {
+	publisher, _, conn := newReconnectingPublisher(t, 0)
+
+	if err := publisher.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if conn.channelCount() != 1 {
+		t.Fatalf("expected no channel reopened after Close, got %d channels", conn.channelCount())
+	}
+}
+
+func TestRabbitMQConsumer_ReopensChannelClosedByBroker(t *testing.T) {
+	consumer, dialer, conn := newReconnectingConsumer(t)
+
+	conn.channel(0).drop()
+	waitFor(t, func() bool { return conn.channelCount() == 2 })
+	waitFor(t, func() bool { return consumer.currentChannel() == rabbitChannel(conn.channel(1)) })
+
+	channel := conn.channel(1)
+	if _, ok := channel.queues["orders"]; !ok || channel.prefetch != 5 {
+		t.Fatalf("expected subscription consumed again on the reopened channel, got queues %v prefetch %d", channel.queues, channel.prefetch)
+	}
+	if dialer.dialCount() != 0 {
+		t.Fatalf("expected the connection kept, got %d dials", dialer.dialCount())
+	}
+}
+
+func TestRabbitMQConsumer_ReopensChannelAfterConsumerCancel(t *testing.T) {
+	consumer, _, conn := newReconnectingConsumer(t)
+	cancelled := conn.channel(0)
+
+	cancelled.cancel("ctag-orders")
+	waitFor(t, func() bool { return conn.channelCount() == 2 })
+	waitFor(t, func() bool { return consumer.currentChannel() == rabbitChannel(conn.channel(1)) })
+
+	if !cancelled.isClosed() {
+		t.Fatal("expected the channel of the cancelled consumer closed")
+	}
+	if _, ok := conn.channel(1).queues["orders"]; !ok {
+		t.Fatalf("expected queue declared again, got %v", conn.channel(1).queues)
+	}
+}
+
+func TestReconnector_CloseStopsReconnecting(t *testing.T) {
+	dialer := &fakeDialer{}
+	r := newReconnector("publisher", dialer.dial, testReconnectRetry(), func(rabbitConnection) error { return nil }, func() {})
+
+	conn := &fakeConnection{}
+	if err := r.start(conn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := r.close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if !conn.closed || dialer.dialCount() != 0 {
+		t.Fatalf("expected connection closed without reconnecting, got %d dials", dialer.dialCount())
+	}
+}
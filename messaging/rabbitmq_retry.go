@@ -0,0 +1,163 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/logging"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	// AttemptsHeader counts the failed attempts of a retried message.
+	AttemptsHeader string = "x-attempts"
+	// HistoryHeader lists the error of the last failed attempts, up to
+	// MaxHistoryEntries.
+	HistoryHeader string = "x-attempt-history"
+	// MaxHistoryEntries bounds HistoryHeader, so a message retried forever
+	// does not grow without limit.
+	MaxHistoryEntries int = 10
+
+	deadLetterExchangeSuffix string = ".dlx"
+	deadLetterQueueSuffix    string = ".dlq"
+	retryQueueSuffix         string = ".retry."
+
+	retryingMsg           string = "retrying message on topic %s (attempt %d failed): %v"
+	deadLetteredMsg       string = "message on topic %s moved to %s after %d attempts: %s"
+	retryPublishErrorMsg  string = "could not republish message on topic %s, requeueing it: %v"
+	historyEntryFormat    string = "attempt %d at %s: %v"
+	historyEntrySeparator string = "; "
+)
+
+func deadLetterExchange(topic string) string {
+	return topic + deadLetterExchangeSuffix
+}
+
+func deadLetterQueue(topic string) string {
+	return topic + deadLetterQueueSuffix
+}
+
+func retryQueue(topic string, delay time.Duration) string {
+	return topic + retryQueueSuffix + delay.String()
+}
+
+// declareRetryTopology declares the dead-letter exchange and queue of the
+// topic and one wait queue per retry delay, whose messages expire back into
+// the main queue. The main queue is left untouched, as changing its
+// arguments would fail on brokers where it already exists.
+func (c *RabbitMQConsumer) declareRetryTopology(sub Subscription, queue string) error {
+	if sub.Retry.MaxAttempts > 0 {
+		dlx, dlq := deadLetterExchange(sub.Topic), deadLetterQueue(sub.Topic)
+		if err := c.channel.ExchangeDeclare(dlx, "fanout", true, false, false, false, nil); err != nil {
+			return fmt.Errorf("failed to declare exchange %s: %w", dlx, err)
+		}
+		if _, err := c.channel.QueueDeclare(dlq, true, false, false, false, nil); err != nil {
+			return fmt.Errorf("failed to declare queue %s: %w", dlq, err)
+		}
+		if err := c.channel.QueueBind(dlq, "", dlx, false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue %s: %w", dlq, err)
+		}
+	}
+
+	for _, delay := range sub.Retry.distinctDelays() {
+		name := retryQueue(sub.Topic, delay)
+		args := amqp.Table{
+			"x-message-ttl":             delay.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": queue,
+		}
+		if _, err := c.channel.QueueDeclare(name, true, false, false, false, args); err != nil {
+			return fmt.Errorf("failed to declare queue %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// retry republishes a failed message with its attempt history, to the wait
+// queue of its delay or straight back to the main queue, or to the dead-letter exchange
+// once the policy is exhausted. The delivery is only acked after the copy was
+// published; otherwise it is requeued as is.
+func (c *RabbitMQConsumer) retry(sub Subscription, queue string, delivery amqp.Delivery, attempt int, cause error) {
+	history := append(attemptHistory(delivery.Headers), fmt.Sprintf(historyEntryFormat, attempt, time.Now().UTC().Format(time.RFC3339), cause))
+	if len(history) > MaxHistoryEntries {
+		history = history[len(history)-MaxHistoryEntries:]
+	}
+
+	headers := amqp.Table{}
+	for k, v := range delivery.Headers {
+		headers[k] = v
+	}
+	headers[AttemptsHeader] = int32(attempt)
+	headers[HistoryHeader] = toTableArray(history)
+
+	exchange, key := "", queue
+	if delay := sub.Retry.delay(attempt); sub.Retry.exhausted(attempt) {
+		exchange, key = deadLetterExchange(sub.Topic), ""
+	} else if delay > 0 {
+		key = retryQueue(sub.Topic, delay)
+	}
+
+	err := errConsumerDisconnected
+	if ch := c.currentChannel(); ch != nil {
+		err = ch.PublishWithContext(context.Background(), exchange, key, false, false, amqp.Publishing{
+			Headers:      headers,
+			ContentType:  delivery.ContentType,
+			DeliveryMode: amqp.Persistent,
+			MessageId:    delivery.MessageId,
+			Timestamp:    delivery.Timestamp,
+			Body:         delivery.Body,
+		})
+	}
+	if err != nil {
+		logging.Error(retryPublishErrorMsg, sub.Topic, err)
+		_ = delivery.Nack(false, true)
+		return
+	}
+
+	if exchange != "" {
+		logging.Error(deadLetteredMsg, sub.Topic, deadLetterQueue(sub.Topic), attempt, strings.Join(history, historyEntrySeparator))
+	} else {
+		logging.Warn(retryingMsg, sub.Topic, attempt, cause)
+	}
+
+	_ = delivery.Ack(false)
+}
+
+func previousAttempts(headers amqp.Table) int {
+	switch v := headers[AttemptsHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
+
+func attemptHistory(headers amqp.Table) []string {
+	entries, _ := headers[HistoryHeader].([]interface{})
+
+	history := make([]string, 0, len(entries)+1)
+	for _, entry := range entries {
+		if s, ok := entry.(string); ok {
+			history = append(history, s)
+		}
+	}
+
+	return history
+}
+
+func toTableArray(values []string) []interface{} {
+	array := make([]interface{}, len(values))
+	for i, v := range values {
+		array[i] = v
+	}
+
+	return array
+}
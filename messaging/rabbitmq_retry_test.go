@@ -0,0 +1,337 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type publishedMessage struct {
+	exchange string
+	key      string
+	msg      amqp.Publishing
+}
+
+type fakeChannel struct {
+	mx          sync.Mutex
+	exchanges   map[string]string
+	queues      map[string]amqp.Table
+	bindings    []string
+	published   []publishedMessage
+	publishErr  error
+	unsupported map[string]bool
+	closed      bool
+	prefetch    int
+	deliveries  chan amqp.Delivery
+	notifyClose []chan *amqp.Error
+	notifyTag   []chan string
+}
+
+func newFakeChannel() *fakeChannel {
+	return &fakeChannel{exchanges: map[string]string{}, queues: map[string]amqp.Table{}}
+}
+
+func (c *fakeChannel) ExchangeDeclare(name, kind string, _, _, _, _ bool, _ amqp.Table) error {
+	if c.unsupported[kind] {
+		c.closed = true
+		return &amqp.Error{Code: amqp.CommandInvalid, Reason: "COMMAND_INVALID - invalid exchange type '" + kind + "'"}
+	}
+	c.exchanges[name] = kind
+	return nil
+}
+
+func (c *fakeChannel) ExchangeBind(destination, key, source string, _ bool, _ amqp.Table) error {
+	c.bindings = append(c.bindings, source+"->"+destination+":"+key)
+	return nil
+}
+
+func (c *fakeChannel) QueueDeclare(name string, _, _, _, _ bool, args amqp.Table) (amqp.Queue, error) {
+	c.queues[name] = args
+	return amqp.Queue{Name: name}, nil
+}
+
+func (c *fakeChannel) QueueBind(name, key, exchange string, _ bool, _ amqp.Table) error {
+	c.bindings = append(c.bindings, exchange+"->"+name+":"+key)
+	return nil
+}
+
+func (c *fakeChannel) Qos(prefetchCount, _ int, _ bool) error {
+	c.prefetch = prefetchCount
+	return nil
+}
+
+func (c *fakeChannel) Consume(string, string, bool, bool, bool, bool, amqp.Table) (<-chan amqp.Delivery, error) {
+	if c.deliveries == nil {
+		c.deliveries = make(chan amqp.Delivery)
+	}
+	return c.deliveries, nil
+}
+
+func (c *fakeChannel) PublishWithContext(_ context.Context, exchange, key string, _, _ bool, msg amqp.Publishing) error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if c.publishErr != nil {
+		return c.publishErr
+	}
+	c.published = append(c.published, publishedMessage{exchange, key, msg})
+	return nil
+}
+
+func (c *fakeChannel) NotifyClose(receiver chan *amqp.Error) chan *amqp.Error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.notifyClose = append(c.notifyClose, receiver)
+	return receiver
+}
+
+func (c *fakeChannel) NotifyCancel(receiver chan string) chan string {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.notifyTag = append(c.notifyTag, receiver)
+	return receiver
+}
+
+func (c *fakeChannel) Close() error {
+	c.shutdown(nil)
+	return nil
+}
+
+// drop simulates the broker closing the channel.
+func (c *fakeChannel) drop() {
+	c.shutdown(&amqp.Error{Code: amqp.PreconditionFailed, Reason: "PRECONDITION_FAILED - unknown delivery tag 1"})
+}
+
+// cancel simulates the broker cancelling the consumer, e.g. when its queue
+// is deleted.
+func (c *fakeChannel) cancel(tag string) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	for _, receiver := range c.notifyTag {
+		receiver <- tag
+	}
+}
+
+func (c *fakeChannel) shutdown(cause *amqp.Error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	for _, receiver := range c.notifyClose {
+		if cause != nil {
+			receiver <- cause
+		}
+		close(receiver)
+	}
+	for _, receiver := range c.notifyTag {
+		close(receiver)
+	}
+	if c.deliveries != nil {
+		close(c.deliveries)
+	}
+}
+
+func (c *fakeChannel) isClosed() bool {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	return c.closed
+}
+
+type fakeAcknowledger struct {
+	acked   bool
+	nacked  bool
+	requeue bool
+}
+
+func (a *fakeAcknowledger) Ack(uint64, bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *fakeAcknowledger) Nack(_ uint64, _ bool, requeue bool) error {
+	a.nacked, a.requeue = true, requeue
+	return nil
+}
+
+func (a *fakeAcknowledger) Reject(_ uint64, requeue bool) error {
+	a.nacked, a.requeue = true, requeue
+	return nil
+}
+
+func newDelivery(headers amqp.Table) (amqp.Delivery, *fakeAcknowledger) {
+	ack := &fakeAcknowledger{}
+	return amqp.Delivery{Acknowledger: ack, Headers: headers, Body: []byte(`{"id":1}`), MessageId: "msg-1"}, ack
+}
+
+func failingSubscription(policy RetryPolicy) Subscription {
+	return Subscription{
+		Topic:   "orders",
+		Handler: func(context.Context, Message) error { return errors.New("payment service down") },
+		Retry:   policy,
+	}
+}
+
+func TestRabbitMQConsumer_DeclaresRetryTopology(t *testing.T) {
+	channel := newFakeChannel()
+	consumer := &RabbitMQConsumer{channel: channel}
+
+	policy := RetryPolicy{MaxAttempts: 5, Delays: []time.Duration{time.Second, 5 * time.Second, 5 * time.Second}}
+	if err := consumer.consume(failingSubscription(policy)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if channel.exchanges["orders.dlx"] != "fanout" {
+		t.Fatalf("expected fanout dead-letter exchange, got %v", channel.exchanges)
+	}
+	if _, ok := channel.queues["orders.dlq"]; !ok {
+		t.Fatalf("expected dead-letter queue, got %v", channel.queues)
+	}
+	if len(channel.queues) != 4 {
+		t.Fatalf("expected main, dead-letter and one queue per distinct delay, got %v", channel.queues)
+	}
+	if args := channel.queues["orders.retry.1s"]; args["x-message-ttl"] != int64(1000) {
+		t.Fatalf("unexpected 1s retry queue arguments: %v", args)
+	}
+	args := channel.queues["orders.retry.5s"]
+	if args["x-message-ttl"] != int64(5000) || args["x-dead-letter-routing-key"] != "orders" || args["x-dead-letter-exchange"] != "" {
+		t.Fatalf("unexpected retry queue arguments: %v", args)
+	}
+	if channel.queues["orders"] != nil {
+		t.Fatalf("expected main queue to be declared without arguments, got %v", channel.queues["orders"])
+	}
+}
+
+func TestRabbitMQConsumer_HandleSuccessAcks(t *testing.T) {
+	channel := newFakeChannel()
+	consumer := &RabbitMQConsumer{channel: channel}
+	delivery, ack := newDelivery(nil)
+
+	var attempt int
+	sub := Subscription{Topic: "orders", Retry: DefaultRetryPolicy(), Handler: func(_ context.Context, msg Message) error {
+		attempt = msg.Attempt
+		return nil
+	}}
+	consumer.handle(sub, "orders", delivery)
+
+	if !ack.acked || len(channel.published) != 0 || attempt != 1 {
+		t.Fatalf("expected ack on first attempt, got ack=%t published=%d attempt=%d", ack.acked, len(channel.published), attempt)
+	}
+}
+
+func TestRabbitMQConsumer_HandleFailureRetries(t *testing.T) {
+	channel := newFakeChannel()
+	consumer := &RabbitMQConsumer{channel: channel}
+	delivery, ack := newDelivery(amqp.Table{"source": "api"})
+
+	consumer.handle(failingSubscription(RetryPolicy{MaxAttempts: 3}), "orders", delivery)
+
+	if !ack.acked || len(channel.published) != 1 {
+		t.Fatalf("expected republish and ack, got ack=%t published=%d", ack.acked, len(channel.published))
+	}
+	published := channel.published[0]
+	if published.exchange != "" || published.key != "orders" {
+		t.Fatalf("expected republish to main queue, got %s/%s", published.exchange, published.key)
+	}
+	if published.msg.Headers[AttemptsHeader] != int32(1) || published.msg.Headers["source"] != "api" || published.msg.MessageId != "msg-1" {
+		t.Fatalf("unexpected republished headers: %+v", published.msg)
+	}
+	if history := attemptHistory(published.msg.Headers); len(history) != 1 || !strings.Contains(history[0], "payment service down") {
+		t.Fatalf("expected attempt history, got %v", history)
+	}
+}
+
+func TestRabbitMQConsumer_HandleFailureUsesDelayTiers(t *testing.T) {
+	channel := newFakeChannel()
+	consumer := &RabbitMQConsumer{channel: channel}
+	sub := failingSubscription(RetryPolicy{MaxAttempts: 10, Delays: []time.Duration{time.Second, 10 * time.Second, time.Minute}})
+
+	for previous := range 4 {
+		delivery, _ := newDelivery(amqp.Table{AttemptsHeader: int32(previous)})
+		consumer.handle(sub, "orders", delivery)
+	}
+
+	expected := []string{"orders.retry.1s", "orders.retry.10s", "orders.retry.1m0s", "orders.retry.1m0s"}
+	for i, key := range expected {
+		if channel.published[i].key != key {
+			t.Fatalf("attempt %d: expected %s, got %s", i+1, key, channel.published[i].key)
+		}
+	}
+}
+
+func TestExponentialDelays(t *testing.T) {
+	delays := ExponentialDelays(time.Second, 10, 3)
+
+	expected := []time.Duration{time.Second, 10 * time.Second, 100 * time.Second}
+	for i := range expected {
+		if delays[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, delays)
+		}
+	}
+}
+
+func TestRabbitMQConsumer_HandleFailureDeadLetters(t *testing.T) {
+	channel := newFakeChannel()
+	consumer := &RabbitMQConsumer{channel: channel}
+	delivery, ack := newDelivery(amqp.Table{
+		AttemptsHeader: int32(2),
+		HistoryHeader:  []interface{}{"attempt 1 at x: boom", "attempt 2 at y: boom"},
+	})
+
+	var attempt int
+	sub := failingSubscription(RetryPolicy{MaxAttempts: 3})
+	handler := sub.Handler
+	sub.Handler = func(ctx context.Context, msg Message) error {
+		attempt = msg.Attempt
+		return handler(ctx, msg)
+	}
+	consumer.handle(sub, "orders", delivery)
+
+	if attempt != 3 {
+		t.Fatalf("expected third attempt, got %d", attempt)
+	}
+	published := channel.published[0]
+	if !ack.acked || published.exchange != "orders.dlx" {
+		t.Fatalf("expected message dead-lettered and acked, got ack=%t exchange=%s", ack.acked, published.exchange)
+	}
+	if history := attemptHistory(published.msg.Headers); len(history) != 3 {
+		t.Fatalf("expected 3 history entries, got %v", history)
+	}
+}
+
+func TestRabbitMQConsumer_HandleFailurePublishErrorRequeues(t *testing.T) {
+	channel := newFakeChannel()
+	channel.publishErr = errors.New("channel closed")
+	consumer := &RabbitMQConsumer{channel: channel}
+	delivery, ack := newDelivery(nil)
+
+	consumer.handle(failingSubscription(DefaultRetryPolicy()), "orders", delivery)
+
+	if ack.acked || !ack.nacked || !ack.requeue {
+		t.Fatalf("expected nack with requeue, got %+v", ack)
+	}
+}
+
+func TestRabbitMQConsumer_HandleFailureCapsHistory(t *testing.T) {
+	channel := newFakeChannel()
+	consumer := &RabbitMQConsumer{channel: channel}
+
+	previous := make([]interface{}, MaxHistoryEntries)
+	for i := range previous {
+		previous[i] = "attempt " + strconv.Itoa(i+1) + " at x: boom"
+	}
+	delivery, _ := newDelivery(amqp.Table{AttemptsHeader: int32(len(previous)), HistoryHeader: previous})
+
+	consumer.handle(failingSubscription(RetryPolicy{Delays: []time.Duration{time.Second}}), "orders", delivery)
+
+	history := attemptHistory(channel.published[0].msg.Headers)
+	if len(history) != MaxHistoryEntries || history[0] != "attempt 2 at x: boom" || !strings.Contains(history[len(history)-1], "payment service down") {
+		t.Fatalf("expected the last %d attempts, got %v", MaxHistoryEntries, history)
+	}
+}
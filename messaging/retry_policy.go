@@ -0,0 +1,70 @@
+package messaging
+
+import "time"
+
+const defaultMaxAttempts int = 5
+
+// RetryPolicy bounds how many times a failing message is handled. After
+// MaxAttempts the message is moved to the dead-letter queue of the topic.
+// MaxAttempts 0 retries forever.
+//
+// Delays are the waits before each retry: the first failure waits Delays[0],
+// the second Delays[1], and the last delay is reused once the list runs out.
+// Each distinct delay gets its own wait queue. Without delays the message is
+// retried right away.
+type RetryPolicy struct {
+	MaxAttempts int
+	Delays      []time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: defaultMaxAttempts,
+		Delays:      []time.Duration{time.Second, 10 * time.Second, time.Minute},
+	}
+}
+
+func WithRetry(policy RetryPolicy) SubscriptionOption {
+	return func(s *Subscription) {
+		s.Retry = policy
+	}
+}
+
+// ExponentialDelays returns tiers delays starting at initial, each one
+// multiplier times the previous.
+func ExponentialDelays(initial time.Duration, multiplier float64, tiers int) []time.Duration {
+	delays := make([]time.Duration, tiers)
+	delay := initial
+	for i := range delays {
+		delays[i] = delay
+		delay = time.Duration(float64(delay) * multiplier)
+	}
+
+	return delays
+}
+
+func (p RetryPolicy) exhausted(attempt int) bool {
+	return p.MaxAttempts > 0 && attempt >= p.MaxAttempts
+}
+
+// delay returns the wait after the given failed attempt.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	if len(p.Delays) == 0 {
+		return 0
+	}
+
+	return p.Delays[min(max(attempt, 1), len(p.Delays))-1]
+}
+
+func (p RetryPolicy) distinctDelays() []time.Duration {
+	seen := make(map[time.Duration]bool, len(p.Delays))
+	delays := make([]time.Duration, 0, len(p.Delays))
+	for _, delay := range p.Delays {
+		if delay > 0 && !seen[delay] {
+			seen[delay] = true
+			delays = append(delays, delay)
+		}
+	}
+
+	return delays
+}
@@ -0,0 +1,12 @@
+package outbox
+
+import "github.com/sdkopen/sdkopen-go/logging"
+
+type relayObserver struct {
+	relay *Relay
+}
+
+func (o relayObserver) Close() {
+	logging.Info("stopping outbox relay for database %s", o.relay.instance)
+	o.relay.Stop()
+}
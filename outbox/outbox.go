@@ -0,0 +1,90 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/sdkopen/sdkopen-go/database"
+	"github.com/sdkopen/sdkopen-go/messaging"
+)
+
+const (
+	Table string = "sdkopen_outbox"
+
+	// IDHeader carries the outbox row id, so that consumers can discard
+	// messages delivered more than once.
+	IDHeader string = "x-outbox-id"
+
+	notInTransactionErrorMsg string = "outbox publish must run inside database.WithTransaction on the same instance"
+)
+
+type message struct {
+	ID           int64
+	Topic        string
+	Body         []byte
+	Headers      sql.NullString
+	DelaySeconds int
+	Attempts     int
+}
+
+// Publish stores the message in the outbox table of the default instance, in
+// the transaction carried by ctx. The relay sends it after the commit.
+func Publish(ctx context.Context, topic string, body []byte, opts ...messaging.PublishOption) error {
+	return PublishOn(ctx, database.DefaultInstance, topic, body, opts...)
+}
+
+func PublishOn(ctx context.Context, instance string, topic string, body []byte, opts ...messaging.PublishOption) error {
+	if !database.InTransactionInInstance(ctx, database.Instance(instance)) {
+		return errors.New(notInTransactionErrorMsg)
+	}
+
+	headers, delay := messaging.ResolvePublishOptions(opts...)
+	encoded, err := encodeHeaders(headers)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := database.InsertInto(Table).
+		Columns("topic", "body", "headers", "delay_seconds").
+		Values(topic, body, encoded, delay).
+		On(instance).
+		Statement(ctx)
+	if err != nil {
+		return err
+	}
+
+	return stmt.Execute()
+}
+
+func encodeHeaders(headers map[string]string) (sql.NullString, error) {
+	if len(headers) == 0 {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(headers)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func (m message) options() ([]messaging.PublishOption, error) {
+	headers := map[string]string{}
+	if m.Headers.Valid {
+		if err := json.Unmarshal([]byte(m.Headers.String), &headers); err != nil {
+			return nil, err
+		}
+	}
+	headers[IDHeader] = strconv.FormatInt(m.ID, 10)
+
+	opts := []messaging.PublishOption{messaging.WithHeaders(headers)}
+	if m.DelaySeconds > 0 {
+		opts = append(opts, messaging.WithDelay(m.DelaySeconds))
+	}
+
+	return opts, nil
+}
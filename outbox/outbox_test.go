@@ -0,0 +1,296 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/common/observer"
+	"github.com/sdkopen/sdkopen-go/database"
+	"github.com/sdkopen/sdkopen-go/messaging"
+)
+
+const testSchema = `CREATE TABLE sdkopen_outbox (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	topic TEXT NOT NULL,
+	body BLOB NOT NULL,
+	headers TEXT,
+	delay_seconds INTEGER NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	sent_at TIMESTAMP,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT
+)`
+
+func init() {
+	observer.Initialize()
+}
+
+type published struct {
+	topic   string
+	body    string
+	headers map[string]string
+	delay   int
+}
+
+type fakePublisher struct {
+	mx        sync.Mutex
+	messages  []published
+	err       error
+	failTopic string
+}
+
+func (p *fakePublisher) Publish(_ context.Context, topic string, body []byte, opts ...messaging.PublishOption) error {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	if topic == p.failTopic {
+		return errors.New("exchange not found")
+	}
+
+	headers, delay := messaging.ResolvePublishOptions(opts...)
+	p.messages = append(p.messages, published{topic, string(body), headers, delay})
+	return nil
+}
+
+func (p *fakePublisher) Close() error {
+	return nil
+}
+
+func (p *fakePublisher) Published() []published {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	return append([]published{}, p.messages...)
+}
+
+func newTestInstance(t *testing.T) string {
+	t.Helper()
+
+	name := strings.ToUpper(strings.ReplaceAll(t.Name(), "/", "_"))
+	t.Setenv(name+"_SQL_DB_NAME", filepath.Join(t.TempDir(), "outbox.db"))
+	database.Register(name, database.SQLiteWithPrefix(name))
+
+	db := database.Instance(name)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	if _, err := db.Exec(testSchema); err != nil {
+		t.Fatalf("could not create outbox table: %v", err)
+	}
+
+	return name
+}
+
+func publishInTransaction(t *testing.T, instance string, topic string, body string, opts ...messaging.PublishOption) {
+	t.Helper()
+
+	err := database.WithTransactionInInstance(context.Background(), database.Instance(instance), func(ctx context.Context) error {
+		return PublishOn(ctx, instance, topic, []byte(body), opts...)
+	})
+	if err != nil {
+		t.Fatalf("could not publish to outbox: %v", err)
+	}
+}
+
+func pendingCount(t *testing.T, instance string) int {
+	t.Helper()
+
+	count, err := database.QueryScalar[int](database.NewStatement(context.Background(), "SELECT COUNT(*) FROM sdkopen_outbox WHERE sent_at IS NULL").On(instance))
+	if err != nil {
+		t.Fatalf("could not count pending messages: %v", err)
+	}
+	return count
+}
+
+func TestPublishOn_RequiresTransaction(t *testing.T) {
+	instance := newTestInstance(t)
+
+	err := PublishOn(context.Background(), instance, "orders", []byte("{}"))
+	if err == nil || err.Error() != notInTransactionErrorMsg {
+		t.Fatalf("expected '%s', got %v", notInTransactionErrorMsg, err)
+	}
+}
+
+func TestPublishOn_RolledBackWithTransaction(t *testing.T) {
+	instance := newTestInstance(t)
+	expectedErr := errors.New("insert order failed")
+
+	err := database.WithTransactionInInstance(context.Background(), database.Instance(instance), func(ctx context.Context) error {
+		if err := PublishOn(ctx, instance, "orders", []byte("{}")); err != nil {
+			return err
+		}
+		return expectedErr
+	})
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("expected %v, got %v", expectedErr, err)
+	}
+
+	if pending := pendingCount(t, instance); pending != 0 {
+		t.Fatalf("expected rolled back message not to be stored, got %d", pending)
+	}
+}
+
+func TestRelay_Flush(t *testing.T) {
+	instance := newTestInstance(t)
+	publisher := &fakePublisher{}
+	relay := NewRelay(WithInstance(instance), WithPublisher(publisher), WithBatchSize(10))
+
+	publishInTransaction(t, instance, "orders", `{"id":1}`, messaging.WithHeaders(map[string]string{"source": "api"}), messaging.WithDelay(5))
+	publishInTransaction(t, instance, "payments", `{"id":2}`)
+
+	sent, err := relay.Flush(context.Background())
+	if err != nil || sent != 2 {
+		t.Fatalf("expected 2 messages sent, got %d, %v", sent, err)
+	}
+
+	messages := publisher.Published()
+	if len(messages) != 2 || messages[0].topic != "orders" || messages[1].topic != "payments" {
+		t.Fatalf("expected messages in insertion order, got %+v", messages)
+	}
+	first := messages[0]
+	if first.body != `{"id":1}` || first.headers["source"] != "api" || first.headers[IDHeader] != "1" || first.delay != 5 {
+		t.Fatalf("unexpected first message: %+v", first)
+	}
+
+	if pending := pendingCount(t, instance); pending != 0 {
+		t.Fatalf("expected all messages marked as sent, got %d pending", pending)
+	}
+	if sent, err = relay.Flush(context.Background()); err != nil || sent != 0 {
+		t.Fatalf("expected nothing left to send, got %d, %v", sent, err)
+	}
+}
+
+func TestRelay_FlushPublishFailure(t *testing.T) {
+	instance := newTestInstance(t)
+	publisher := &fakePublisher{err: errors.New("broker unavailable")}
+	relay := NewRelay(WithInstance(instance), WithPublisher(publisher))
+
+	publishInTransaction(t, instance, "orders", "{}")
+	publishInTransaction(t, instance, "orders", "{}")
+
+	sent, err := relay.Flush(context.Background())
+	if err == nil || sent != 0 {
+		t.Fatalf("expected publish failure, got %d, %v", sent, err)
+	}
+
+	type failure struct {
+		Attempts  int
+		LastError sql.NullString
+	}
+	failures, err := database.Query[failure](database.NewStatement(context.Background(), "SELECT attempts, last_error FROM sdkopen_outbox ORDER BY id").On(instance))
+	if err != nil {
+		t.Fatalf("could not read outbox: %v", err)
+	}
+	if failures[0].Attempts != 1 || !strings.Contains(failures[0].LastError.String, "broker unavailable") {
+		t.Fatalf("expected failure recorded on first message, got %+v", failures[0])
+	}
+	if failures[1].Attempts != 0 {
+		t.Fatalf("expected relay to stop at the first failure, got %+v", failures[1])
+	}
+	if pending := pendingCount(t, instance); pending != 2 {
+		t.Fatalf("expected messages to stay pending, got %d", pending)
+	}
+}
+
+func TestRelay_StartAndStop(t *testing.T) {
+	instance := newTestInstance(t)
+	publisher := &fakePublisher{}
+	relay := NewRelay(WithInstance(instance), WithPublisher(publisher), WithPollInterval(10*time.Millisecond))
+
+	relay.Start()
+	publishInTransaction(t, instance, "orders", "{}")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(publisher.Published()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	relay.Stop()
+
+	if len(publisher.Published()) != 1 {
+		t.Fatalf("expected relay to send the message, got %+v", publisher.Published())
+	}
+	relay.Stop()
+}
+
+func TestRelay_StopWithoutStart(t *testing.T) {
+	relay := NewRelay(WithPublisher(&fakePublisher{}))
+
+	stopped := make(chan struct{})
+	go func() {
+		relay.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("expected Stop to return for a relay that never started")
+	}
+
+	relay.Start()
+	relay.Stop()
+}
+
+func TestRelay_StartTwice(t *testing.T) {
+	instance := newTestInstance(t)
+	relay := NewRelay(WithInstance(instance), WithPublisher(&fakePublisher{}), WithPollInterval(10*time.Millisecond))
+
+	relay.Start()
+	relay.Start()
+	relay.Stop()
+}
+
+func TestRelay_InvalidOptionsKeepDefaults(t *testing.T) {
+	relay := NewRelay(WithBatchSize(0), WithPollInterval(-time.Second))
+
+	if relay.batchSize != defaultBatchSize || relay.pollInterval != defaultPollInterval {
+		t.Fatalf("expected defaults, got batch size %d and poll interval %s", relay.batchSize, relay.pollInterval)
+	}
+}
+
+func TestRelay_FlushParksExhaustedMessage(t *testing.T) {
+	instance := newTestInstance(t)
+	publisher := &fakePublisher{failTopic: "invalid"}
+	relay := NewRelay(WithInstance(instance), WithPublisher(publisher), WithMaxAttempts(2))
+
+	publishInTransaction(t, instance, "invalid", "{}")
+	publishInTransaction(t, instance, "orders", "{}")
+
+	for range 2 {
+		if sent, err := relay.Flush(context.Background()); err == nil || sent != 0 {
+			t.Fatalf("expected the failing message to hold the outbox, got %d, %v", sent, err)
+		}
+	}
+
+	sent, err := relay.Flush(context.Background())
+	if err != nil || sent != 1 {
+		t.Fatalf("expected the message after the parked one sent, got %d, %v", sent, err)
+	}
+	if messages := publisher.Published(); len(messages) != 1 || messages[0].topic != "orders" {
+		t.Fatalf("expected only the orders message published, got %+v", messages)
+	}
+	if pending := pendingCount(t, instance); pending != 1 {
+		t.Fatalf("expected the parked message to stay unsent, got %d pending", pending)
+	}
+}
+
+func TestRelay_WithMaxAttemptsZeroKeepsRetrying(t *testing.T) {
+	instance := newTestInstance(t)
+	relay := NewRelay(WithInstance(instance), WithPublisher(&fakePublisher{failTopic: "invalid"}), WithMaxAttempts(0))
+
+	publishInTransaction(t, instance, "invalid", "{}")
+	publishInTransaction(t, instance, "orders", "{}")
+
+	for range defaultMaxAttempts + 1 {
+		if sent, err := relay.Flush(context.Background()); err == nil || sent != 0 {
+			t.Fatalf("expected the failing message to keep holding the outbox, got %d, %v", sent, err)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sdkopen/sdkopen-go/common/observer"
+	"github.com/sdkopen/sdkopen-go/database"
+	"github.com/sdkopen/sdkopen-go/logging"
+	"github.com/sdkopen/sdkopen-go/messaging"
+)
+
+const (
+	defaultBatchSize    int           = 100
+	defaultPollInterval time.Duration = time.Second
+	defaultMaxAttempts  int           = 10
+
+	dbNotInitializedErrorMsg string = "database %s not initialized"
+	relayFailedErrorMsg      string = "outbox relay failed: %v"
+	publishFailedErrorMsg    string = "could not publish outbox message %d to %s: %w"
+	messageParkedMsg         string = "outbox message %d to %s parked after %d attempts, reset its attempts to send it again: %v"
+)
+
+type PublishFunc func(ctx context.Context, topic string, body []byte, opts ...messaging.PublishOption) error
+
+type RelayOption func(*Relay)
+
+// Relay sends unsent outbox rows through the messaging publisher and marks
+// them as sent. Rows are locked with FOR UPDATE SKIP LOCKED, so several
+// replicas can run a relay on the same table.
+type Relay struct {
+	instance     string
+	batchSize    int
+	pollInterval time.Duration
+	maxAttempts  int
+	publish      PublishFunc
+	stop         chan struct{}
+	done         chan struct{}
+	mx           sync.Mutex
+	started      bool
+	startOnce    sync.Once
+	stopOnce     sync.Once
+}
+
+func NewRelay(opts ...RelayOption) *Relay {
+	relay := &Relay{
+		instance:     database.DefaultInstance,
+		batchSize:    defaultBatchSize,
+		pollInterval: defaultPollInterval,
+		maxAttempts:  defaultMaxAttempts,
+		publish:      messaging.Publish,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(relay)
+	}
+
+	return relay
+}
+
+func WithInstance(name string) RelayOption {
+	return func(r *Relay) {
+		r.instance = name
+	}
+}
+
+// WithBatchSize sets how many rows each relay transaction sends. Values
+// below 1 keep the default.
+func WithBatchSize(size int) RelayOption {
+	return func(r *Relay) {
+		if size <= 0 {
+			size = defaultBatchSize
+		}
+		r.batchSize = size
+	}
+}
+
+// WithPollInterval sets the wait between reads once the outbox is drained.
+// Non-positive values keep the default.
+func WithPollInterval(interval time.Duration) RelayOption {
+	return func(r *Relay) {
+		if interval <= 0 {
+			interval = defaultPollInterval
+		}
+		r.pollInterval = interval
+	}
+}
+
+// WithMaxAttempts sets how many failed publishes a row gets before the relay
+// parks it: the row stays unsent and is skipped, so it no longer holds back
+// the rows after it. 0 retries forever, keeping the outbox in order; negative
+// values keep the default.
+func WithMaxAttempts(attempts int) RelayOption {
+	return func(r *Relay) {
+		if attempts < 0 {
+			attempts = defaultMaxAttempts
+		}
+		r.maxAttempts = attempts
+	}
+}
+
+// WithPublisher sends the messages through p instead of the publisher set up
+// by messaging.Initialize.
+func WithPublisher(p messaging.Publisher) RelayOption {
+	return func(r *Relay) {
+		r.publish = p.Publish
+	}
+}
+
+// Start polls the outbox in background until Stop is called or the service
+// shuts down. Calls after the first, or after Stop, do nothing.
+func (r *Relay) Start() {
+	r.startOnce.Do(func() {
+		if err := observer.Attach(relayObserver{r}); err != nil {
+			logging.Error("could not attach outbox relay to observer: %v", err)
+			return
+		}
+
+		r.mx.Lock()
+		defer r.mx.Unlock()
+
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+
+		r.started = true
+		go r.run()
+		logging.Info("outbox relay started for database %s", r.instance)
+	})
+}
+
+// Stop waits for the batch in progress, if any. It returns right away when
+// the relay never ran.
+func (r *Relay) Stop() {
+	r.stopOnce.Do(func() {
+		r.mx.Lock()
+		defer r.mx.Unlock()
+
+		close(r.stop)
+		if !r.started {
+			close(r.done)
+		}
+	})
+	<-r.done
+}
+
+func (r *Relay) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := r.relayBatch()
+		if err != nil {
+			logging.Error(relayFailedErrorMsg, err)
+		}
+
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+
+		// A full batch means there may be more rows waiting.
+		if err == nil && sent == r.batchSize {
+			continue
+		}
+
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) relayBatch() (int, error) {
+	wg := observer.GetWaitGroup()
+	wg.Add(1)
+	defer wg.Done()
+
+	return r.Flush(context.Background())
+}
+
+// Flush sends one batch of unsent rows and returns how many were sent. It
+// stops at the first publish failure, so that rows keep their order, and
+// records the failure in the row. Rows that used up their attempts are
+// skipped.
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	db := database.Instance(r.instance)
+	if db == nil {
+		return 0, fmt.Errorf(dbNotInitializedErrorMsg, r.instance)
+	}
+
+	sent := 0
+	var publishErr error
+	err := database.WithTransactionInInstance(ctx, db, func(ctx context.Context) error {
+		sent, publishErr = 0, nil
+
+		query := database.Select("id", "topic", "body", "headers", "delay_seconds", "attempts").
+			From(Table).
+			Where(database.IsNull("sent_at"))
+		if r.maxAttempts > 0 {
+			query.Where(database.Lt("attempts", r.maxAttempts))
+		}
+
+		stmt, err := query.
+			OrderBy("id").
+			Limit(r.batchSize).
+			SkipLocked().
+			On(r.instance).
+			Statement(ctx)
+		if err != nil {
+			return err
+		}
+
+		messages, err := database.Query[message](stmt)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range messages {
+			if publishErr = r.send(ctx, m); publishErr != nil {
+				if r.maxAttempts > 0 && m.Attempts+1 >= r.maxAttempts {
+					logging.Error(messageParkedMsg, m.ID, m.Topic, m.Attempts+1, publishErr)
+				}
+				return r.markFailed(ctx, m.ID, publishErr)
+			}
+			if err := r.markSent(ctx, m.ID); err != nil {
+				return err
+			}
+			sent++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return sent, publishErr
+}
+
+func (r *Relay) send(ctx context.Context, m message) error {
+	opts, err := m.options()
+	if err == nil {
+		err = r.publish(ctx, m.Topic, m.Body, opts...)
+	}
+	if err != nil {
+		return fmt.Errorf(publishFailedErrorMsg, m.ID, m.Topic, err)
+	}
+
+	return nil
+}
+
+func (r *Relay) markSent(ctx context.Context, id int64) error {
+	stmt, err := database.Update(Table).
+		SetExpr("sent_at", "CURRENT_TIMESTAMP").
+		Where(database.Eq("id", id)).
+		On(r.instance).
+		Statement(ctx)
+	if err != nil {
+		return err
+	}
+
+	return stmt.Execute()
+}
+
+func (r *Relay) markFailed(ctx context.Context, id int64, cause error) error {
+	stmt, err := database.Update(Table).
+		SetExpr("attempts", "attempts + 1").
+		Set("last_error", cause.Error()).
+		Where(database.Eq("id", id)).
+		On(r.instance).
+		Statement(ctx)
+	if err != nil {
+		return err
+	}
+
+	return stmt.Execute()
+}
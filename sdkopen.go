@@ -7,13 +7,16 @@ import (
 	"github.com/sdkopen/sdkopen-go/common/observer"
 	"github.com/sdkopen/sdkopen-go/database"
 	"github.com/sdkopen/sdkopen-go/messaging"
+	"github.com/sdkopen/sdkopen-go/outbox"
 	"github.com/sdkopen/sdkopen-go/validator"
 	"github.com/sdkopen/sdkopen-go/webserver"
 )
 
 type SdkOpenOptions struct {
 	Database  func() *sql.DB
+	Databases map[string]func() *sql.DB
 	Messaging func() *messaging.Provider
+	Outbox    func() *outbox.Relay
 	WebServer func() webserver.Server
 }
 
@@ -29,11 +32,23 @@ func Initialize(opts *SdkOpenOptions) {
 		database.Initialize(opts.Database)
 	}
 
+	for name, factory := range opts.Databases {
+		database.Register(name, factory)
+	}
+
+	if opts.Database != nil || len(opts.Databases) > 0 {
+		webserver.RegisterErrorStatusMapper(database.ErrorStatus)
+	}
+
 	if opts.Messaging != nil {
 		messaging.Initialize(opts.Messaging())
 		go messaging.StartConsumer()
 	}
 
+	if opts.Outbox != nil {
+		opts.Outbox().Start()
+	}
+
 	if opts.WebServer != nil {
 		webserver.ListenAndServe(opts.WebServer)
 	}
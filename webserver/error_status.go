@@ -0,0 +1,25 @@
+package webserver
+
+import commonhttp "github.com/sdkopen/sdkopen-go/common/http"
+
+// ErrorStatusMapper returns the status code for errors it recognises, such as
+// database.ErrorStatus for constraint violations and missing rows.
+type ErrorStatusMapper func(err error) (commonhttp.HttpStatusCode, bool)
+
+var ServerErrorMappers []ErrorStatusMapper
+
+func RegisterErrorStatusMapper(mapper ErrorStatusMapper) {
+	ServerErrorMappers = append(ServerErrorMappers, mapper)
+}
+
+// ErrorStatus returns the status of the first mapper that recognises err, or
+// fallback when none does.
+func ErrorStatus(err error, fallback commonhttp.HttpStatusCode) commonhttp.HttpStatusCode {
+	for _, mapper := range ServerErrorMappers {
+		if status, ok := mapper(err); ok {
+			return status
+		}
+	}
+
+	return fallback
+}
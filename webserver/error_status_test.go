@@ -0,0 +1,29 @@
+package webserver
+
+import (
+	"errors"
+	"testing"
+
+	commonhttp "github.com/sdkopen/sdkopen-go/common/http"
+)
+
+var errDuplicated = errors.New("duplicated")
+
+func TestErrorStatus(t *testing.T) {
+	ServerErrorMappers = nil
+	t.Cleanup(func() { ServerErrorMappers = nil })
+
+	RegisterErrorStatusMapper(func(err error) (commonhttp.HttpStatusCode, bool) {
+		if errors.Is(err, errDuplicated) {
+			return commonhttp.StatusConflict, true
+		}
+		return 0, false
+	})
+
+	if status := ErrorStatus(errDuplicated, commonhttp.StatusInternalServerError); status != commonhttp.StatusConflict {
+		t.Fatalf("expected 409, got %d", status)
+	}
+	if status := ErrorStatus(errors.New("other"), commonhttp.StatusInternalServerError); status != commonhttp.StatusInternalServerError {
+		t.Fatalf("expected fallback 500, got %d", status)
+	}
+}
@@ -0,0 +1,91 @@
+package webserver
+
+import (
+	"context"
+	"time"
+
+	commonhttp "github.com/sdkopen/sdkopen-go/common/http"
+)
+
+const (
+	defaultLivenessPath     string        = "/health/live"
+	defaultReadinessPath    string        = "/health/ready"
+	defaultReadinessTimeout time.Duration = 5 * time.Second
+
+	healthStatusUp   string = "UP"
+	healthStatusDown string = "DOWN"
+)
+
+type ReadinessCheck func(ctx context.Context) (detail any, ready bool)
+
+type HealthResponse struct {
+	Status string         `json:"status"`
+	Checks map[string]any `json:"checks,omitempty"`
+}
+
+type namedReadinessCheck struct {
+	name  string
+	check ReadinessCheck
+}
+
+type HealthController struct {
+	livenessPath  string
+	readinessPath string
+	timeout       time.Duration
+	checks        []namedReadinessCheck
+}
+
+func NewHealthController() *HealthController {
+	return &HealthController{
+		livenessPath:  defaultLivenessPath,
+		readinessPath: defaultReadinessPath,
+		timeout:       defaultReadinessTimeout,
+	}
+}
+
+func (c *HealthController) WithPaths(liveness, readiness string) *HealthController {
+	c.livenessPath = liveness
+	c.readinessPath = readiness
+	return c
+}
+
+func (c *HealthController) WithTimeout(timeout time.Duration) *HealthController {
+	c.timeout = timeout
+	return c
+}
+
+func (c *HealthController) WithReadinessCheck(name string, check ReadinessCheck) *HealthController {
+	c.checks = append(c.checks, namedReadinessCheck{name, check})
+	return c
+}
+
+func (c *HealthController) Routes() []Route {
+	return []Route{
+		{Path: c.livenessPath, HttpMethod: commonhttp.Get, Function: c.liveness},
+		{Path: c.readinessPath, HttpMethod: commonhttp.Get, Function: c.readiness},
+	}
+}
+
+func (c *HealthController) liveness(ctx WebContext) {
+	ctx.JsonResponse(commonhttp.StatusOK, HealthResponse{Status: healthStatusUp})
+}
+
+func (c *HealthController) readiness(ctx WebContext) {
+	checkCtx, cancel := context.WithTimeout(ctx.Context(), c.timeout)
+	defer cancel()
+
+	response := HealthResponse{Status: healthStatusUp, Checks: make(map[string]any, len(c.checks))}
+	for _, named := range c.checks {
+		detail, ready := named.check(checkCtx)
+		response.Checks[named.name] = detail
+		if !ready {
+			response.Status = healthStatusDown
+		}
+	}
+
+	if response.Status == healthStatusDown {
+		ctx.JsonResponse(commonhttp.StatusServiceUnavailable, response)
+		return
+	}
+	ctx.JsonResponse(commonhttp.StatusOK, response)
+}
@@ -0,0 +1,63 @@
+package webserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestHealthController_Routes(t *testing.T) {
+	routes := NewHealthController().WithPaths("/live", "/ready").Routes()
+
+	if len(routes) != 2 || routes[0].Path != "/live" || routes[1].Path != "/ready" {
+		t.Fatalf("unexpected routes: %+v", routes)
+	}
+}
+
+func TestHealthController_Liveness(t *testing.T) {
+	ctx, rec := newTestContext("GET", "/health/live", "")
+
+	NewHealthController().liveness(ctx)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+}
+
+func TestHealthController_Readiness(t *testing.T) {
+	controller := NewHealthController().
+		WithReadinessCheck("database", func(context.Context) (any, bool) { return "ok", true })
+
+	ctx, rec := newTestContext("GET", "/health/ready", "")
+	controller.readiness(ctx)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var response HealthResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("expected json body, got %v", err)
+	}
+	if response.Status != "UP" || response.Checks["database"] != "ok" {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+}
+
+func TestHealthController_ReadinessDown(t *testing.T) {
+	controller := NewHealthController().
+		WithReadinessCheck("database", func(context.Context) (any, bool) { return "ok", true }).
+		WithReadinessCheck("broker", func(context.Context) (any, bool) { return "unreachable", false })
+
+	ctx, rec := newTestContext("GET", "/health/ready", "")
+	controller.readiness(ctx)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+	var response HealthResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &response)
+	if response.Status != "DOWN" || response.Checks["broker"] != "unreachable" {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+}